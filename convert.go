@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"terraform-provider-dbsnapper/internal/export"
+)
+
+// runConvert writes the targets of a dbsnapper CLI configuration file as
+// dbsnapper_target resource blocks. The settings that cannot be represented
+// are listed on stderr and as comments in the configuration.
+//
+//	terraform-provider-dbsnapper convert -config ~/.config/dbsnapper/dbsnapper.yml -out targets.tf
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	config := flags.String("config", "", "the dbsnapper CLI configuration file to convert")
+	out := flags.String("out", "", "write the configuration to this file instead of stdout, the file must not exist yet")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *config == "" {
+		return fmt.Errorf("missing the dbsnapper CLI configuration file, set it with -config")
+	}
+
+	conversions, unsupported, err := export.ReadCLIConfig(*config)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := export.WriteConversions(w, conversions); err != nil {
+		return err
+	}
+
+	// Flag everything that was left out
+	for _, setting := range unsupported {
+		fmt.Fprintf(os.Stderr, "Skipped %s: only targets are converted\n", setting)
+	}
+	for _, c := range conversions {
+		for _, setting := range c.Unsupported {
+			fmt.Fprintf(os.Stderr, "Skipped target %s setting %s: not supported by dbsnapper_target\n", c.Target.Name, setting)
+		}
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d targets to %s\n", len(conversions), *out)
+	}
+	return nil
+}
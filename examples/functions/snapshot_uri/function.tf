@@ -0,0 +1,26 @@
+locals {
+  snapshots = provider::dbsnapper::snapshot_uri({
+    sp_provider = dbsnapper_storage_profile.s3.sp_provider
+    account_id  = dbsnapper_storage_profile.s3.account_id
+    bucket      = dbsnapper_storage_profile.s3.bucket
+    prefix      = dbsnapper_storage_profile.s3.prefix
+  }, dbsnapper_target.orders.name, timestamp())
+}
+
+# Expire the snapshots below the prefix of the storage profile
+resource "aws_s3_bucket_lifecycle_configuration" "snapshots" {
+  bucket = local.snapshots.bucket
+
+  rule {
+    id     = "expire-snapshots"
+    status = "Enabled"
+
+    filter {
+      prefix = local.snapshots.prefix
+    }
+
+    expiration {
+      days = 30
+    }
+  }
+}
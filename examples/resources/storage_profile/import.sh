@@ -0,0 +1,5 @@
+# Import a storage profile by its ID
+terraform import dbsnapper_storage_profile.tf_sp_example 0c7a9e2b-1f4d-4a6e-8b3c-5d7f9a1b3c5e
+
+# Import a storage profile by its name
+terraform import dbsnapper_storage_profile.tf_sp_example name:tf_sp_example
@@ -0,0 +1,5 @@
+# Import a target by its ID
+terraform import dbsnapper_target.tf_example 6b1d3c1e-5e2a-4c8b-9f3d-2a4e6c8b0d1f
+
+# Import a target by its name
+terraform import dbsnapper_target.tf_example name:tf_example
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"terraform-provider-dbsnapper/internal/client"
+	"terraform-provider-dbsnapper/internal/export"
+)
+
+// runExport writes every target and storage profile of the account as an
+// import block plus a matching resource block, ready for terraform plan.
+//
+//	DBSNAPPER_AUTHTOKEN=... terraform-provider-dbsnapper export -out imported.tf
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "", "write the configuration to this file instead of stdout, the file must not exist yet")
+	baseURL := flags.String("base-url", os.Getenv("DBSNAPPER_BASE_URL"), "the DBSnapper API URL, defaults to DBSNAPPER_BASE_URL or the production API")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	authtoken := os.Getenv("DBSNAPPER_AUTHTOKEN")
+	if authtoken == "" {
+		return fmt.Errorf("missing DBSnapper API authtoken, set the DBSNAPPER_AUTHTOKEN environment variable")
+	}
+	if *baseURL == "" {
+		*baseURL = client.BaseURLProduction
+	}
+
+	ctx := context.Background()
+	dbs := client.NewDBSnapper(authtoken, *baseURL)
+	if !dbs.IsReady {
+		return fmt.Errorf("failed to create DBSnapper API client: API not ready")
+	}
+
+	storageProfiles, err := dbs.GetStorageProfiles(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list storage profiles: %w", err)
+	}
+	targets, err := dbs.ListTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list targets: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		// Like terraform plan -generate-config-out, never overwrite existing configuration
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := export.Write(w, storageProfiles, targets); err != nil {
+		return err
+	}
+
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d storage profiles and %d targets to %s\n", len(storageProfiles), len(targets), *out)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+)
+
+// API is the part of the DBSnapper API the provider uses. The provider passes
+// it to the resources and data sources, so tests can substitute it and
+// decorators such as InstrumentedAPI can wrap it.
+type API interface {
+	// Targets
+	CreateTarget(ctx context.Context, t *target.Target) (*target.Target, error)
+	GetTarget(ctx context.Context, id string) (*target.Target, error)
+	UpdateTarget(ctx context.Context, id string, t *target.Target) (*target.Target, error)
+	DeleteTarget(ctx context.Context, id string) error
+	ListTargets(ctx context.Context) ([]target.Target, error)
+
+	// Storage profiles
+	CreateStorageProfile(ctx context.Context, sp *storage.StorageProfile) (*storage.StorageProfile, error)
+	GetStorageProfile(ctx context.Context, id string) (*storage.StorageProfile, error)
+	UpdateStorageProfile(ctx context.Context, id string, sp *storage.StorageProfile) (*storage.StorageProfile, error)
+	DeleteStorageProfile(ctx context.Context, id string) error
+	GetStorageProfiles(ctx context.Context) ([]storage.StorageProfile, error)
+}
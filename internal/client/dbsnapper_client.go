@@ -1,22 +1,143 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
 	"github.com/joescharf/dbsnapper/v2/apiv1"
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
 )
 
+// BaseURLProduction is the URL of the production DBSnapper API.
+const BaseURLProduction = "https://app.dbsnapper.com/api/v3"
+
+// DBSnapper adapts the apiv1 client to the API interface. The apiv1 methods
+// take no context, so the adapter does not pass it on, except to the reads
+// the provider polls, which return once the context ends, see withContext.
 type DBSnapper struct {
 	IsReady bool
-	API     *apiv1.APIV1
+	api     *apiv1.APIV1
+
+	// authtoken and baseURL are kept for the requests apiv1 does not cover
+	authtoken  string
+	baseURL    string
+	httpClient *http.Client
 }
 
+var _ API = (*DBSnapper)(nil)
+
 func NewDBSnapper(authtoken, baseURL string) *DBSnapper {
 	api := apiv1.NewClient(authtoken, baseURL)
 
 	d := &DBSnapper{
-		API: api,
+		api:        api,
+		authtoken:  authtoken,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
 	}
 
 	d.IsReady = api.IsReady()
 
 	return d
 }
+
+////////////////////////////// TARGETS //////////////////////////////
+
+func (d *DBSnapper) CreateTarget(ctx context.Context, t *target.Target) (*target.Target, error) {
+	return d.api.CreateTarget(t)
+}
+
+func (d *DBSnapper) GetTarget(ctx context.Context, id string) (*target.Target, error) {
+	return withContext(ctx, func() (*target.Target, error) { return d.api.GetTarget(id) })
+}
+
+func (d *DBSnapper) UpdateTarget(ctx context.Context, id string, t *target.Target) (*target.Target, error) {
+	return d.api.UpdateTarget(id, t)
+}
+
+func (d *DBSnapper) DeleteTarget(ctx context.Context, id string) error {
+	return d.api.DeleteTarget(id)
+}
+
+// ListTargets lists the targets of the account. apiv1 GetTargetsTF drops the
+// error, so that a failed request reads as an account without targets, the
+// adapter therefore requests the list itself.
+func (d *DBSnapper) ListTargets(ctx context.Context) ([]target.Target, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.baseURL+"/targets", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.authtoken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET /targets: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var targets []target.Target
+	if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return nil, fmt.Errorf("GET /targets: unable to decode the targets: %w", err)
+	}
+	return targets, nil
+}
+
+////////////////////////////// STORAGE PROFILES //////////////////////////////
+
+func (d *DBSnapper) CreateStorageProfile(ctx context.Context, sp *storage.StorageProfile) (*storage.StorageProfile, error) {
+	return d.api.CreateStorageProfile(sp)
+}
+
+func (d *DBSnapper) GetStorageProfile(ctx context.Context, id string) (*storage.StorageProfile, error) {
+	return d.api.GetStorageProfile(id)
+}
+
+func (d *DBSnapper) UpdateStorageProfile(ctx context.Context, id string, sp *storage.StorageProfile) (*storage.StorageProfile, error) {
+	return d.api.UpdateStorageProfile(id, sp)
+}
+
+func (d *DBSnapper) DeleteStorageProfile(ctx context.Context, id string) error {
+	return d.api.DeleteStorageProfile(id)
+}
+
+func (d *DBSnapper) GetStorageProfiles(ctx context.Context) ([]storage.StorageProfile, error) {
+	return d.api.GetStorageProfiles()
+}
+
+// withContext returns the result of call, or the error of the context when it
+// ends first. apiv1 requests cannot be cancelled, an abandoned request runs
+// to completion in the background and its result is dropped.
+func withContext[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	if ctx.Done() == nil {
+		return call()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListTargets(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		body    string
+		want    []string
+		wantErr string
+	}{
+		"targets": {
+			status: http.StatusOK,
+			body:   `[{"name": "production"}, {"name": "staging"}]`,
+			want:   []string{"production", "staging"},
+		},
+		"no targets": {
+			status: http.StatusOK,
+			body:   `[]`,
+		},
+		"unauthorized": {
+			status:  http.StatusUnauthorized,
+			body:    `{"error": "invalid authtoken"}`,
+			wantErr: "401 Unauthorized",
+		},
+		"server error": {
+			status:  http.StatusInternalServerError,
+			body:    `{"error": "internal"}`,
+			wantErr: "500 Internal Server Error",
+		},
+		"invalid response": {
+			status:  http.StatusOK,
+			body:    `<html>`,
+			wantErr: "unable to decode",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/api/targets" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer authtoken" {
+					t.Errorf("unexpected Authorization header %q", got)
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			d := NewDBSnapper("authtoken", server.URL+"/api/")
+			targets, err := d.ListTargets(context.Background())
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected an error containing %q, got: %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var got []string
+			for _, target := range targets {
+				got = append(got, target.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("expected targets %v, got: %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetTargetContextDeadline(t *testing.T) {
+	// The server holds the request until the test ends
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewDBSnapper("token", server.URL).GetTarget(ctx, "6b1d3c1e-5e2a-4c8b-9f3d-2a4e6c8b0d1f")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetTarget() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetTarget() returned after %s, want it to return at the deadline", elapsed)
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+)
+
+// Call describes a finished call of an API method.
+type Call struct {
+	Method   string
+	Duration time.Duration
+	Err      error
+}
+
+// CallStats accumulates the calls of an API method.
+type CallStats struct {
+	Calls    int
+	Errors   int
+	Duration time.Duration
+}
+
+// InstrumentedAPI decorates an API, it counts and times every call and
+// reports it to the observer with the context of the call, e.g. to log it.
+type InstrumentedAPI struct {
+	next    API
+	observe func(context.Context, Call)
+
+	mu    sync.Mutex
+	stats map[string]CallStats
+}
+
+var _ API = (*InstrumentedAPI)(nil)
+
+// NewInstrumentedAPI returns the decorated API, the observer may be nil.
+func NewInstrumentedAPI(next API, observe func(context.Context, Call)) *InstrumentedAPI {
+	return &InstrumentedAPI{
+		next:    next,
+		observe: observe,
+		stats:   map[string]CallStats{},
+	}
+}
+
+// Stats returns a copy of the statistics by method name.
+func (a *InstrumentedAPI) Stats() map[string]CallStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := make(map[string]CallStats, len(a.stats))
+	for method, s := range a.stats {
+		stats[method] = s
+	}
+	return stats
+}
+
+// record is deferred by every method with the start time and a pointer to
+// the named error result.
+func (a *InstrumentedAPI) record(ctx context.Context, method string, start time.Time, err *error) {
+	call := Call{Method: method, Duration: time.Since(start), Err: *err}
+
+	a.mu.Lock()
+	s := a.stats[method]
+	s.Calls++
+	if call.Err != nil {
+		s.Errors++
+	}
+	s.Duration += call.Duration
+	a.stats[method] = s
+	a.mu.Unlock()
+
+	if a.observe != nil {
+		a.observe(ctx, call)
+	}
+}
+
+////////////////////////////// TARGETS //////////////////////////////
+
+func (a *InstrumentedAPI) CreateTarget(ctx context.Context, t *target.Target) (_ *target.Target, err error) {
+	defer a.record(ctx, "CreateTarget", time.Now(), &err)
+	return a.next.CreateTarget(ctx, t)
+}
+
+func (a *InstrumentedAPI) GetTarget(ctx context.Context, id string) (_ *target.Target, err error) {
+	defer a.record(ctx, "GetTarget", time.Now(), &err)
+	return a.next.GetTarget(ctx, id)
+}
+
+func (a *InstrumentedAPI) UpdateTarget(ctx context.Context, id string, t *target.Target) (_ *target.Target, err error) {
+	defer a.record(ctx, "UpdateTarget", time.Now(), &err)
+	return a.next.UpdateTarget(ctx, id, t)
+}
+
+func (a *InstrumentedAPI) DeleteTarget(ctx context.Context, id string) (err error) {
+	defer a.record(ctx, "DeleteTarget", time.Now(), &err)
+	return a.next.DeleteTarget(ctx, id)
+}
+
+func (a *InstrumentedAPI) ListTargets(ctx context.Context) (_ []target.Target, err error) {
+	defer a.record(ctx, "ListTargets", time.Now(), &err)
+	return a.next.ListTargets(ctx)
+}
+
+////////////////////////////// STORAGE PROFILES //////////////////////////////
+
+func (a *InstrumentedAPI) CreateStorageProfile(ctx context.Context, sp *storage.StorageProfile) (_ *storage.StorageProfile, err error) {
+	defer a.record(ctx, "CreateStorageProfile", time.Now(), &err)
+	return a.next.CreateStorageProfile(ctx, sp)
+}
+
+func (a *InstrumentedAPI) GetStorageProfile(ctx context.Context, id string) (_ *storage.StorageProfile, err error) {
+	defer a.record(ctx, "GetStorageProfile", time.Now(), &err)
+	return a.next.GetStorageProfile(ctx, id)
+}
+
+func (a *InstrumentedAPI) UpdateStorageProfile(ctx context.Context, id string, sp *storage.StorageProfile) (_ *storage.StorageProfile, err error) {
+	defer a.record(ctx, "UpdateStorageProfile", time.Now(), &err)
+	return a.next.UpdateStorageProfile(ctx, id, sp)
+}
+
+func (a *InstrumentedAPI) DeleteStorageProfile(ctx context.Context, id string) (err error) {
+	defer a.record(ctx, "DeleteStorageProfile", time.Now(), &err)
+	return a.next.DeleteStorageProfile(ctx, id)
+}
+
+func (a *InstrumentedAPI) GetStorageProfiles(ctx context.Context) (_ []storage.StorageProfile, err error) {
+	defer a.record(ctx, "GetStorageProfiles", time.Now(), &err)
+	return a.next.GetStorageProfiles(ctx)
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joescharf/dbsnapper/v2/models/target"
+)
+
+// stubAPI implements the target methods the tests call, the other methods
+// of the embedded nil interface panic.
+type stubAPI struct {
+	API
+	err error
+}
+
+func (s *stubAPI) GetTarget(_ context.Context, id string) (*target.Target, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &target.Target{Name: id}, nil
+}
+
+func (s *stubAPI) DeleteTarget(_ context.Context, id string) error {
+	return s.err
+}
+
+func (s *stubAPI) ListTargets(_ context.Context) ([]target.Target, error) {
+	return []target.Target{{Name: "one"}, {Name: "two"}}, s.err
+}
+
+// requestKey marks the context of a call, the observer must receive it.
+type requestKey struct{}
+
+func TestInstrumentedAPI(t *testing.T) {
+	errAPI := errors.New("500 Internal Server Error")
+
+	tests := map[string]struct {
+		err  error
+		call func(ctx context.Context, api API) error
+		want Call
+	}{
+		"get target": {
+			call: func(ctx context.Context, api API) error {
+				got, err := api.GetTarget(ctx, "orders")
+				if err == nil && got.Name != "orders" {
+					t.Errorf("expected the target of the decorated API, got: %s", got.Name)
+				}
+				return err
+			},
+			want: Call{Method: "GetTarget"},
+		},
+		"get target error": {
+			err: errAPI,
+			call: func(ctx context.Context, api API) error {
+				_, err := api.GetTarget(ctx, "orders")
+				return err
+			},
+			want: Call{Method: "GetTarget", Err: errAPI},
+		},
+		"delete target error": {
+			err: errAPI,
+			call: func(ctx context.Context, api API) error {
+				return api.DeleteTarget(ctx, "orders")
+			},
+			want: Call{Method: "DeleteTarget", Err: errAPI},
+		},
+		"list targets": {
+			call: func(ctx context.Context, api API) error {
+				got, err := api.ListTargets(ctx)
+				if len(got) != 2 {
+					t.Errorf("expected 2 targets, got: %d", len(got))
+				}
+				return err
+			},
+			want: Call{Method: "ListTargets"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), requestKey{}, name)
+			var calls []Call
+			api := NewInstrumentedAPI(&stubAPI{err: test.err}, func(ctx context.Context, call Call) {
+				if got := ctx.Value(requestKey{}); got != name {
+					t.Errorf("expected the context of the call, got request %v", got)
+				}
+				calls = append(calls, call)
+			})
+
+			for i := 0; i < 2; i++ {
+				if err := test.call(ctx, api); !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got: %v", test.err, err)
+				}
+			}
+
+			if len(calls) != 2 {
+				t.Fatalf("expected 2 observed calls, got: %d", len(calls))
+			}
+			for _, call := range calls {
+				if call.Method != test.want.Method || !errors.Is(call.Err, test.want.Err) {
+					t.Errorf("expected call %s with error %v, got: %s with error %v", test.want.Method, test.want.Err, call.Method, call.Err)
+				}
+				if call.Duration < 0 {
+					t.Errorf("expected a non-negative duration, got: %s", call.Duration)
+				}
+			}
+
+			stats := api.Stats()
+			if len(stats) != 1 {
+				t.Fatalf("expected stats for 1 method, got: %v", stats)
+			}
+			s := stats[test.want.Method]
+			wantErrors := 0
+			if test.want.Err != nil {
+				wantErrors = 2
+			}
+			if s.Calls != 2 || s.Errors != wantErrors {
+				t.Errorf("expected 2 calls and %d errors, got: %d calls and %d errors", wantErrors, s.Calls, s.Errors)
+			}
+		})
+	}
+}
+
+func TestInstrumentedAPIWithoutObserver(t *testing.T) {
+	api := NewInstrumentedAPI(&stubAPI{}, nil)
+	if _, err := api.GetTarget(context.Background(), "orders"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := api.Stats()["GetTarget"].Calls; got != 1 {
+		t.Errorf("expected 1 call, got: %d", got)
+	}
+}
@@ -0,0 +1,248 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"gopkg.in/yaml.v3"
+)
+
+// Conversion is a target read from the configuration file of the dbsnapper
+// CLI, mapped onto the API target that dbsnapper_target manages.
+type Conversion struct {
+	Target target.Target
+
+	// Unsupported lists the settings of the target that cannot be represented
+	// in dbsnapper_target and are left out of the configuration.
+	Unsupported []string
+}
+
+// ReadCLIConfig reads the targets of the dbsnapper CLI configuration file,
+// sorted by name. Settings outside of the targets, like the CLI defaults and
+// storage profiles, are returned as unsupported.
+func ReadCLIConfig(path string) ([]Conversion, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the dbsnapper configuration: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the dbsnapper configuration %s: %w", path, err)
+	}
+
+	var unsupported []string
+	for key := range config {
+		if key != "targets" {
+			unsupported = append(unsupported, key)
+		}
+	}
+	sort.Strings(unsupported)
+
+	targets, ok := config["targets"].(map[string]interface{})
+	if !ok {
+		return nil, unsupported, fmt.Errorf("the dbsnapper configuration %s has no targets", path)
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conversions := make([]Conversion, 0, len(names))
+	for _, name := range names {
+		r := &cliConfigReader{dir: filepath.Dir(path)}
+		t := r.target(name, r.mapping("", targets[name]))
+		conversions = append(conversions, Conversion{Target: t, Unsupported: r.unsupported})
+	}
+
+	return conversions, unsupported, nil
+}
+
+// WriteConversions writes a resource block for every converted target. The
+// unsupported settings of a target are written as a comment above its block.
+func WriteConversions(w io.Writer, conversions []Conversion) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	labels := make(map[string]bool)
+
+	for _, c := range conversions {
+		if len(c.Unsupported) > 0 {
+			lines := []string{"The following settings of the dbsnapper CLI configuration cannot be represented and were left out:"}
+			for _, setting := range c.Unsupported {
+				lines = append(lines, "  - "+setting)
+			}
+			appendComment(body, lines)
+		}
+		writeTarget(body, uniqueLabel(labels, c.Target.Name), c.Target, nil)
+	}
+
+	if _, err := w.Write(hclwrite.Format(f.Bytes())); err != nil {
+		return fmt.Errorf("unable to write configuration: %w", err)
+	}
+	return nil
+}
+
+// appendComment appends a line comment for each of the lines.
+func appendComment(body *hclwrite.Body, lines []string) {
+	for _, line := range lines {
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte("# " + line + "\n")},
+		})
+	}
+}
+
+// cliConfigReader maps the settings of a single CLI target and collects the
+// settings it cannot map.
+type cliConfigReader struct {
+	// dir is the directory of the configuration file, query files are relative to it
+	dir         string
+	unsupported []string
+}
+
+// target maps a CLI target onto the API target, the same target that the
+// provider sends for a dbsnapper_target resource.
+func (r *cliConfigReader) target(name string, settings map[string]interface{}) target.Target {
+	t := target.Target{Name: name}
+
+	for key, value := range settings {
+		switch key {
+		case "name":
+			t.Name = r.str(key, value)
+		case "snapshot":
+			r.snapshot(&t, r.mapping(key, value))
+		case "sanitize":
+			r.sanitize(&t, r.mapping(key, value))
+		case "share":
+			r.share(&t, r.mapping(key, value))
+		default:
+			r.unsupported = append(r.unsupported, key)
+		}
+	}
+
+	sort.Strings(r.unsupported)
+	return t
+}
+
+func (r *cliConfigReader) snapshot(t *target.Target, settings map[string]interface{}) {
+	for key, value := range settings {
+		path := "snapshot." + key
+		switch key {
+		case "src_url":
+			t.Snapshot.SrcURL = r.str(path, value)
+		case "dst_url":
+			t.Snapshot.DstURL = r.str(path, value)
+		case "storage_profile":
+			// The CLI references storage profiles by name, dbsnapper_target by ID
+			r.unsupported = append(r.unsupported, fmt.Sprintf("%s (%v), set snapshot.storage_profile.id to the ID of the storage profile", path, value))
+		default:
+			r.unsupported = append(r.unsupported, path)
+		}
+	}
+}
+
+func (r *cliConfigReader) sanitize(t *target.Target, settings map[string]interface{}) {
+	// The query is the first of override_query, query and the content of query_file
+	var overrideQuery, query, queryFile string
+
+	for key, value := range settings {
+		path := "sanitize." + key
+		switch key {
+		case "dst_url":
+			t.Sanitize.DstURL = r.str(path, value)
+		case "override_query":
+			overrideQuery = r.str(path, value)
+		case "query":
+			query = r.str(path, value)
+		case "query_file":
+			queryFile = r.str(path, value)
+		case "storage_profile":
+			r.unsupported = append(r.unsupported, fmt.Sprintf("%s (%v), set sanitize.storage_profile.id to the ID of the storage profile", path, value))
+		default:
+			r.unsupported = append(r.unsupported, path)
+		}
+	}
+
+	switch {
+	case overrideQuery != "":
+		t.Sanitize.Query = overrideQuery
+	case query != "":
+		t.Sanitize.Query = query
+	case queryFile != "":
+		if !filepath.IsAbs(queryFile) {
+			queryFile = filepath.Join(r.dir, queryFile)
+		}
+		content, err := os.ReadFile(queryFile)
+		if err != nil {
+			r.unsupported = append(r.unsupported, fmt.Sprintf("sanitize.query_file (%s), unable to read the query file: %s", queryFile, err))
+			return
+		}
+		t.Sanitize.Query = string(content)
+	}
+}
+
+func (r *cliConfigReader) share(t *target.Target, settings map[string]interface{}) {
+	for key, value := range settings {
+		path := "share." + key
+		switch key {
+		case "sso_groups":
+			t.Share.SsoGroups = r.strs(path, value)
+		default:
+			r.unsupported = append(r.unsupported, path)
+		}
+	}
+}
+
+// mapping returns the value as a YAML mapping, a value of another type is unsupported.
+func (r *cliConfigReader) mapping(path string, value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		r.unsupported = append(r.unsupported, fmt.Sprintf("%s (expected a mapping)", path))
+	}
+	return m
+}
+
+// list returns the value as a YAML sequence, a value of another type is unsupported.
+func (r *cliConfigReader) list(path string, value interface{}) []interface{} {
+	if value == nil {
+		return nil
+	}
+	l, ok := value.([]interface{})
+	if !ok {
+		r.unsupported = append(r.unsupported, fmt.Sprintf("%s (expected a list)", path))
+	}
+	return l
+}
+
+// str returns the value as a string, a value of another type is unsupported.
+func (r *cliConfigReader) str(path string, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	s, ok := value.(string)
+	if !ok {
+		r.unsupported = append(r.unsupported, fmt.Sprintf("%s (expected a string)", path))
+	}
+	return s
+}
+
+// strs returns the value as a list of strings, a value of another type is unsupported.
+func (r *cliConfigReader) strs(path string, value interface{}) []string {
+	var values []string
+	for i, v := range r.list(path, value) {
+		if s := r.str(fmt.Sprintf("%s[%d]", path, i), v); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,247 @@
+// Package export writes existing DBSnapper targets and storage profiles as
+// Terraform configuration, an import block plus a matching resource block for
+// each of them, so that resources created in the DBSnapper UI can be brought
+// under Terraform management.
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	targetResourceType         = "dbsnapper_target"
+	storageProfileResourceType = "dbsnapper_storage_profile"
+)
+
+// invalidLabelChars matches the characters that are replaced in a Terraform
+// resource name. Dashes are allowed in resource names, but not replacing them
+// would let a-b and a_b collide in the variable names derived from the label.
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Write writes an import block and a resource block for every storage profile
+// and target. Targets reference the exported storage profiles by address, and
+// the storage profile credentials are read from sensitive variables so that
+// they are not written to the configuration.
+func Write(w io.Writer, storageProfiles []storage.StorageProfile, targets []target.Target) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	labels := make(map[string]bool)
+
+	// Storage profile addresses, used by the targets to reference them
+	storageProfileRefs := make(map[uuid.UUID]hcl.Traversal, len(storageProfiles))
+
+	for _, sp := range storageProfiles {
+		label := uniqueLabel(labels, sp.Name)
+		storageProfileRefs[sp.ID] = hcl.Traversal{
+			hcl.TraverseRoot{Name: storageProfileResourceType},
+			hcl.TraverseAttr{Name: label},
+			hcl.TraverseAttr{Name: "id"},
+		}
+
+		writeImport(body, storageProfileResourceType, label, sp.ID)
+		writeStorageProfile(body, label, sp)
+	}
+
+	for _, t := range targets {
+		label := uniqueLabel(labels, t.Name)
+
+		writeImport(body, targetResourceType, label, t.ID)
+		writeTarget(body, label, t, storageProfileRefs)
+	}
+
+	if _, err := w.Write(hclwrite.Format(f.Bytes())); err != nil {
+		return fmt.Errorf("unable to write configuration: %w", err)
+	}
+	return nil
+}
+
+// writeImport appends the import block of the resource.
+func writeImport(body *hclwrite.Body, resourceType, label string, id uuid.UUID) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	block.SetAttributeValue("id", cty.StringVal(id.String()))
+	body.AppendNewline()
+}
+
+// writeStorageProfile appends the resource block of the storage profile and
+// the variables holding its credentials.
+func writeStorageProfile(body *hclwrite.Body, label string, sp storage.StorageProfile) {
+	// The labels are unique and already normalized, see uniqueLabel
+	accessKeyVar := label + "_access_key"
+	secretKeyVar := label + "_secret_key"
+
+	for _, name := range []string{accessKeyVar, secretKeyVar} {
+		variable := body.AppendNewBlock("variable", []string{name}).Body()
+		variable.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		variable.SetAttributeValue("sensitive", cty.True)
+		body.AppendNewline()
+	}
+
+	var attributes object
+	attributes.setString("name", sp.Name)
+	attributes.setString("sp_provider", sp.Provider)
+	attributes.setString("region", sp.Region)
+	attributes.setString("account_id", sp.AccountID)
+	attributes.set("access_key", variableTokens(accessKeyVar))
+	attributes.set("secret_key", variableTokens(secretKeyVar))
+	attributes.setString("bucket", sp.Bucket)
+	attributes.setString("prefix", sp.Prefix)
+
+	attributes.appendResource(body, storageProfileResourceType, label)
+}
+
+// writeTarget appends the resource block of the target.
+func writeTarget(body *hclwrite.Body, label string, t target.Target, storageProfileRefs map[uuid.UUID]hcl.Traversal) {
+	var attributes object
+	attributes.setString("name", t.Name)
+
+	// Snapshot
+	var snapshot object
+	snapshot.setString("src_url", t.Snapshot.SrcURL)
+	snapshot.setString("dst_url", t.Snapshot.DstURL)
+	snapshot.setObject("storage_profile", storageProfileObject(t.Snapshot.StorageProfile.ID, storageProfileRefs))
+	attributes.setObject("snapshot", snapshot)
+
+	// Sanitize
+	var sanitize object
+	sanitize.setString("dst_url", t.Sanitize.DstURL)
+	sanitize.setString("query", t.Sanitize.Query)
+	if t.Sanitize.StorageProfile != nil {
+		sanitize.setObject("storage_profile", storageProfileObject(t.Sanitize.StorageProfile.ID, storageProfileRefs))
+	}
+	attributes.setObject("sanitize", sanitize)
+
+	// Share
+	var share object
+	share.setStrings("sso_groups", t.Share.SsoGroups)
+	attributes.setObject("share", share)
+
+	attributes.appendResource(body, targetResourceType, label)
+}
+
+// storageProfileObject returns the storage_profile attribute of a target,
+// referencing the exported storage profile when there is one.
+func storageProfileObject(id uuid.UUID, storageProfileRefs map[uuid.UUID]hcl.Traversal) object {
+	var sp object
+	if id == uuid.Nil {
+		return sp
+	}
+
+	if ref, ok := storageProfileRefs[id]; ok {
+		sp.set("id", hclwrite.TokensForTraversal(ref))
+	} else {
+		sp.setString("id", id.String())
+	}
+	return sp
+}
+
+// stringTokens returns the tokens of a string value. Multi-line values ending
+// in a newline, like most SQL queries, are written as heredoc so that they stay
+// readable, every other value is quoted.
+func stringTokens(value string) hclwrite.Tokens {
+	if !strings.Contains(strings.TrimSuffix(value, "\n"), "\n") || !strings.HasSuffix(value, "\n") {
+		return hclwrite.TokensForValue(cty.StringVal(value))
+	}
+
+	// Pick a delimiter that does not occur as a line of the value
+	delimiter := "EOT"
+	for strings.Contains("\n"+value, "\n"+delimiter+"\n") {
+		delimiter += "_"
+	}
+
+	// Heredocs are templates, escape the template sequences
+	content := strings.NewReplacer("${", "$${", "%{", "%%{").Replace(value)
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + delimiter + "\n")},
+		{Type: hclsyntax.TokenStringLit, Bytes: []byte(content)},
+		{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(delimiter)},
+	}
+}
+
+// variableTokens returns the tokens of a reference to the input variable.
+func variableTokens(name string) hclwrite.Tokens {
+	return hclwrite.TokensForTraversal(hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: name},
+	})
+}
+
+// uniqueLabel turns the name into a valid Terraform resource name that is not
+// in labels yet, and adds it to labels.
+func uniqueLabel(labels map[string]bool, name string) string {
+	base := strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" || !(base[0] >= 'a' && base[0] <= 'z') {
+		base = "r_" + base
+	}
+
+	label := base
+	for i := 2; labels[label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	labels[label] = true
+	return label
+}
+
+// object collects the attributes of a resource or an object attribute value,
+// leaving out the unset ones so that the configuration only holds what the
+// API returned.
+type object []hclwrite.ObjectAttrTokens
+
+func (o *object) set(name string, value hclwrite.Tokens) {
+	*o = append(*o, hclwrite.ObjectAttrTokens{
+		Name:  hclwrite.TokensForIdentifier(name),
+		Value: value,
+	})
+}
+
+func (o *object) setString(name, value string) {
+	if value != "" {
+		o.set(name, stringTokens(value))
+	}
+}
+
+func (o *object) setStrings(name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	elements := make([]cty.Value, len(values))
+	for i, v := range values {
+		elements[i] = cty.StringVal(v)
+	}
+	o.set(name, hclwrite.TokensForValue(cty.ListVal(elements)))
+}
+
+func (o *object) setObject(name string, value object) {
+	if len(value) > 0 {
+		o.set(name, value.tokens())
+	}
+}
+
+func (o object) tokens() hclwrite.Tokens {
+	return hclwrite.TokensForObject(o)
+}
+
+// appendResource appends the attributes as a resource block.
+func (o object) appendResource(body *hclwrite.Body, resourceType, label string) {
+	block := body.AppendNewBlock("resource", []string{resourceType, label}).Body()
+	for _, attr := range o {
+		block.SetAttributeRaw(string(attr.Name.Bytes()), attr.Value)
+	}
+	body.AppendNewline()
+}
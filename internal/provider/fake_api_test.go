@@ -0,0 +1,346 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+)
+
+// fakeAPIToken is the authtoken the fake API accepts.
+const fakeAPIToken = "fake-authtoken"
+
+// fakeAPI is an in-process fake of the DBSnapper v3 API, for unit tests that
+// point the provider at it through base_url. It serves the routes used by the
+// API client:
+//
+//	GET, POST          /targets
+//	GET, PUT, DELETE   /targets/{id}
+//	GET, POST          /storage_profiles
+//	GET, PUT, DELETE   /storage_profiles/{id}
+//
+// Errors are injected with fail.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	clock           time.Time
+	targets         map[uuid.UUID]target.Target
+	storageProfiles map[uuid.UUID]storage.StorageProfile
+	faults          []*fakeFault
+}
+
+// fakeFault is an error response injected into the fake API.
+type fakeFault struct {
+	method string
+	path   string
+	status int
+	times  int
+}
+
+// newFakeAPI starts a fake API that is closed when the test ends.
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	f := &fakeAPI{
+		clock:           time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		targets:         make(map[uuid.UUID]target.Target),
+		storageProfiles: make(map[uuid.UUID]storage.StorageProfile),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /targets", f.listTargets)
+	mux.HandleFunc("POST /targets", f.createTarget)
+	mux.HandleFunc("GET /targets/{id}", f.getTarget)
+	mux.HandleFunc("PUT /targets/{id}", f.updateTarget)
+	mux.HandleFunc("DELETE /targets/{id}", f.deleteTarget)
+	mux.HandleFunc("GET /storage_profiles", f.listStorageProfiles)
+	mux.HandleFunc("POST /storage_profiles", f.createStorageProfile)
+	mux.HandleFunc("GET /storage_profiles/{id}", f.getStorageProfile)
+	mux.HandleFunc("PUT /storage_profiles/{id}", f.updateStorageProfile)
+	mux.HandleFunc("DELETE /storage_profiles/{id}", f.deleteStorageProfile)
+
+	f.Server = httptest.NewServer(f.middleware(mux))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// fail makes the next times requests with the method and a path starting
+// with path respond with the HTTP status, e.g. 404, 429 or 500.
+func (f *fakeAPI) fail(method, path string, status, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fakeFault{method: method, path: path, status: status, times: times})
+}
+
+// middleware authenticates the requests, injects the faults, and serializes
+// the access to the fake data.
+func (f *fakeAPI) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer "+fakeAPIToken {
+			writeFakeError(w, http.StatusUnauthorized, "invalid authtoken")
+			return
+		}
+
+		for _, fault := range f.faults {
+			if fault.times > 0 && fault.method == r.Method && strings.HasPrefix(r.URL.Path, fault.path) {
+				fault.times--
+				writeFakeError(w, fault.status, http.StatusText(fault.status))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// now advances the clock of the fake API by a second and returns it.
+func (f *fakeAPI) now() string {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock.Format(time.RFC3339)
+}
+
+// writeFakeJSON writes the value as the JSON response.
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeFakeError writes an error response.
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]string{"error": message})
+}
+
+// readFakeJSON decodes the JSON request body into v, and responds with 400
+// when that fails.
+func readFakeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// fakeID parses the id of the request path, and responds with 404 when the
+// id is not valid.
+func fakeID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeFakeError(w, http.StatusNotFound, fmt.Sprintf("%q not found", r.PathValue("id")))
+		return id, false
+	}
+	return id, true
+}
+
+// sortedValues returns the values of the map in the order of less.
+func sortedValues[T any](m map[uuid.UUID]T, less func(a, b T) bool) []T {
+	values := make([]T, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return less(values[i], values[j]) })
+	return values
+}
+
+////////////////////////////// TARGETS //////////////////////////////
+
+// addTarget stores the target as if it was created through the API, and returns it.
+func (f *fakeAPI) addTarget(t target.Target) target.Target {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.storeTarget(uuid.New(), t, "")
+}
+
+// target returns the stored target.
+func (f *fakeAPI) target(id string) (target.Target, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.targets[uuid.MustParse(id)]
+	return t, ok
+}
+
+// modifyTarget changes the stored target out of band.
+func (f *fakeAPI) modifyTarget(id string, modify func(t *target.Target)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := f.targets[uuid.MustParse(id)]
+	modify(&t)
+	f.targets[t.ID] = t
+}
+
+// targetCount returns the number of stored targets.
+func (f *fakeAPI) targetCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.targets)
+}
+
+// storeTarget stores the target with the id, keeping createdAt when it is set.
+func (f *fakeAPI) storeTarget(id uuid.UUID, t target.Target, createdAt string) target.Target {
+	now := f.now()
+	if createdAt == "" {
+		createdAt = now
+	}
+	t.ID = id
+	t.Status = "ready"
+	t.Messages = ""
+	t.Snapshot.SrcBytes = 1024
+	t.CreatedAt = createdAt
+	t.UpdatedAt = now
+	f.targets[id] = t
+	return t
+}
+
+func (f *fakeAPI) listTargets(w http.ResponseWriter, r *http.Request) {
+	writeFakeJSON(w, http.StatusOK, sortedValues(f.targets, func(a, b target.Target) bool { return a.Name < b.Name }))
+}
+
+func (f *fakeAPI) createTarget(w http.ResponseWriter, r *http.Request) {
+	var t target.Target
+	if !readFakeJSON(w, r, &t) {
+		return
+	}
+	writeFakeJSON(w, http.StatusCreated, f.storeTarget(uuid.New(), t, ""))
+}
+
+func (f *fakeAPI) getTarget(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	t, ok := f.targets[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "target not found")
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, t)
+}
+
+func (f *fakeAPI) updateTarget(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	existing, ok := f.targets[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "target not found")
+		return
+	}
+	var t target.Target
+	if !readFakeJSON(w, r, &t) {
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, f.storeTarget(id, t, existing.CreatedAt))
+}
+
+func (f *fakeAPI) deleteTarget(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := f.targets[id]; !ok {
+		writeFakeError(w, http.StatusNotFound, "target not found")
+		return
+	}
+	delete(f.targets, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+////////////////////////////// STORAGE PROFILES //////////////////////////////
+
+// storageProfile returns the stored storage profile.
+func (f *fakeAPI) storageProfile(id string) (storage.StorageProfile, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sp, ok := f.storageProfiles[uuid.MustParse(id)]
+	return sp, ok
+}
+
+// storageProfileCount returns the number of stored storage profiles.
+func (f *fakeAPI) storageProfileCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.storageProfiles)
+}
+
+// storeStorageProfile stores the storage profile with the id, keeping createdAt when it is set.
+func (f *fakeAPI) storeStorageProfile(id uuid.UUID, sp storage.StorageProfile, createdAt string) storage.StorageProfile {
+	now := f.now()
+	if createdAt == "" {
+		createdAt = now
+	}
+	sp.ID = id
+	sp.Status = "ready"
+	sp.CreatedAt = createdAt
+	sp.UpdatedAt = now
+	f.storageProfiles[id] = sp
+	return sp
+}
+
+func (f *fakeAPI) listStorageProfiles(w http.ResponseWriter, r *http.Request) {
+	writeFakeJSON(w, http.StatusOK, sortedValues(f.storageProfiles, func(a, b storage.StorageProfile) bool { return a.Name < b.Name }))
+}
+
+func (f *fakeAPI) createStorageProfile(w http.ResponseWriter, r *http.Request) {
+	var sp storage.StorageProfile
+	if !readFakeJSON(w, r, &sp) {
+		return
+	}
+	writeFakeJSON(w, http.StatusCreated, f.storeStorageProfile(uuid.New(), sp, ""))
+}
+
+func (f *fakeAPI) getStorageProfile(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	sp, ok := f.storageProfiles[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "storage profile not found")
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, sp)
+}
+
+func (f *fakeAPI) updateStorageProfile(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	existing, ok := f.storageProfiles[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "storage profile not found")
+		return
+	}
+	var sp storage.StorageProfile
+	if !readFakeJSON(w, r, &sp) {
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, f.storeStorageProfile(id, sp, existing.CreatedAt))
+}
+
+func (f *fakeAPI) deleteStorageProfile(w http.ResponseWriter, r *http.Request) {
+	id, ok := fakeID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := f.storageProfiles[id]; !ok {
+		writeFakeError(w, http.StatusNotFound, "storage profile not found")
+		return
+	}
+	delete(f.storageProfiles, id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// importNamePrefix marks an import ID that identifies the resource by name
+// instead of by UUID, e.g. name:production.
+const importNamePrefix = "name:"
+
+// importName returns the name of an import ID in the form name:<name>.
+func importName(importID string) (string, bool) {
+	name, ok := strings.CutPrefix(importID, importNamePrefix)
+	if !ok {
+		return "", false
+	}
+	return name, true
+}
+
+// resolveImportName returns the ID of the only item with the name. It fails
+// when no item or more than one item has the name, as the import would
+// otherwise be ambiguous.
+func resolveImportName[T any](kind string, name string, items []T, nameOf func(T) string, idOf func(T) string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("Expected import identifier with format: %s<name>, got an empty name", importNamePrefix)
+	}
+
+	var ids []string
+	for _, item := range items {
+		if nameOf(item) == name {
+			ids = append(ids, idOf(item))
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("No %s named %q was found", kind, name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Found %d %ss named %q, with IDs %s. Import one of them by ID instead", len(ids), kind, name, strings.Join(ids, ", "))
+	}
+}
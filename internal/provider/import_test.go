@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportName(t *testing.T) {
+	testCases := map[string]struct {
+		importID string
+		wantName string
+		wantOK   bool
+	}{
+		"uuid":            {importID: "6b1d3c1e-5e2a-4c8b-9f3d-2a4e6c8b0d1f"},
+		"name":            {importID: "name:production", wantName: "production", wantOK: true},
+		"name with colon": {importID: "name:team:production", wantName: "team:production", wantOK: true},
+		"empty name":      {importID: "name:", wantOK: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gotName, gotOK := importName(tc.importID)
+			if gotName != tc.wantName || gotOK != tc.wantOK {
+				t.Errorf("importName(%q) = %q, %t, want %q, %t", tc.importID, gotName, gotOK, tc.wantName, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolveImportName(t *testing.T) {
+	type item struct{ id, name string }
+	items := []item{
+		{id: "id-1", name: "production"},
+		{id: "id-2", name: "staging"},
+		{id: "id-3", name: "staging"},
+	}
+
+	testCases := map[string]struct {
+		name    string
+		wantID  string
+		wantErr string
+	}{
+		"unique name":    {name: "production", wantID: "id-1"},
+		"ambiguous name": {name: "staging", wantErr: "Found 2 targets named \"staging\", with IDs id-2, id-3"},
+		"unknown name":   {name: "development", wantErr: "No target named \"development\" was found"},
+		"empty name":     {name: "", wantErr: "got an empty name"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			id, err := resolveImportName("target", tc.name, items,
+				func(i item) string { return i.name },
+				func(i item) string { return i.id },
+			)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if id != tc.wantID {
+				t.Errorf("id = %q, want %q", id, tc.wantID)
+			}
+		})
+	}
+}
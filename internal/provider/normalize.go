@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The API does not distinguish an omitted optional attribute from an empty
+// one, it returns the zero value for both. The function below maps an API
+// value of an optional attribute back onto the Terraform value, using the
+// prior value of the attribute from the plan or state: a zero API value is
+// null when the prior value was null, e.g. when the attribute is omitted in
+// the configuration or the resource is imported, and the zero value when it
+// was configured as such. Otherwise Terraform reports an inconsistent result
+// after apply.
+
+// optionalString maps the API value of an optional string attribute.
+func optionalString(prior types.String, value string) types.String {
+	if value == "" && prior.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	dbsTargetModel "github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+)
+
+func TestOptionalString(t *testing.T) {
+	testCases := map[string]struct {
+		prior types.String
+		value string
+		want  types.String
+	}{
+		"empty with null prior":     {prior: types.StringNull(), value: "", want: types.StringNull()},
+		"empty with empty prior":    {prior: types.StringValue(""), value: "", want: types.StringValue("")},
+		"empty with unknown prior":  {prior: types.StringUnknown(), value: "", want: types.StringValue("")},
+		"empty with changed prior":  {prior: types.StringValue("us-east-1"), value: "", want: types.StringValue("")},
+		"value with null prior":     {prior: types.StringNull(), value: "us-east-1", want: types.StringValue("us-east-1")},
+		"value with matching prior": {prior: types.StringValue("us-east-1"), value: "us-east-1", want: types.StringValue("us-east-1")},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := optionalString(tc.prior, tc.value); !got.Equal(tc.want) {
+				t.Errorf("optionalString() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAPIResponseToSPResourceModelOptionalAttributes(t *testing.T) {
+	response := storage.StorageProfile{
+		ID:        uuid.MustParse("0c7e9a4e-6f0b-4d7a-9a55-2f0b5c1d7e3a"),
+		Name:      "tf_test",
+		Provider:  "s3",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Bucket:    "snapshots",
+	}
+
+	testCases := map[string]struct {
+		prior    StorageProfileResourceModel
+		response func(*storage.StorageProfile)
+		want     map[string]types.String
+	}{
+		"omitted in configuration": {
+			prior: StorageProfileResourceModel{
+				Region:    types.StringNull(),
+				AccountID: types.StringNull(),
+				Prefix:    types.StringNull(),
+			},
+			want: map[string]types.String{
+				"region":     types.StringNull(),
+				"account_id": types.StringNull(),
+				"prefix":     types.StringNull(),
+			},
+		},
+		"configured as empty strings": {
+			prior: StorageProfileResourceModel{
+				Region:    types.StringValue(""),
+				AccountID: types.StringValue(""),
+				Prefix:    types.StringValue(""),
+			},
+			want: map[string]types.String{
+				"region":     types.StringValue(""),
+				"account_id": types.StringValue(""),
+				"prefix":     types.StringValue(""),
+			},
+		},
+		"imported": {
+			response: func(sp *storage.StorageProfile) {
+				sp.Region = "us-west-2"
+			},
+			want: map[string]types.String{
+				"region":     types.StringValue("us-west-2"),
+				"account_id": types.StringNull(),
+				"prefix":     types.StringNull(),
+			},
+		},
+		"set by the API": {
+			prior: StorageProfileResourceModel{
+				Region:    types.StringNull(),
+				AccountID: types.StringNull(),
+				Prefix:    types.StringValue("prod"),
+			},
+			response: func(sp *storage.StorageProfile) {
+				sp.AccountID = "abc123"
+				sp.Prefix = "prod"
+			},
+			want: map[string]types.String{
+				"region":     types.StringNull(),
+				"account_id": types.StringValue("abc123"),
+				"prefix":     types.StringValue("prod"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			sp := response
+			if tc.response != nil {
+				tc.response(&sp)
+			}
+
+			got, err := APIResponseToSPResourceModel(context.Background(), &sp, &tc.prior)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			attributes := map[string]types.String{
+				"region":     got.Region,
+				"account_id": got.AccountID,
+				"prefix":     got.Prefix,
+			}
+			for attribute, want := range tc.want {
+				if !attributes[attribute].Equal(want) {
+					t.Errorf("%s = %s, want %s", attribute, attributes[attribute], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIResponseToResourceModelOptionalAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		prior    TargetResourceModel
+		response dbsTargetModel.Target
+		check    func(t *testing.T, got *TargetResourceModel)
+	}{
+		"snapshot attributes omitted in configuration": {
+			prior: TargetResourceModel{
+				Snapshot: &targetSnapshotModel{
+					SrcURL: types.StringValue("postgres://localhost/tf_test"),
+					DstURL: types.StringNull(),
+				},
+			},
+			response: dbsTargetModel.Target{Snapshot: dbsTargetModel.SnapshotCfg{SrcURL: "postgres://localhost/tf_test"}},
+			check: func(t *testing.T, got *TargetResourceModel) {
+				if !got.Snapshot.DstURL.IsNull() {
+					t.Errorf("snapshot.dst_url = %s, want null", got.Snapshot.DstURL)
+				}
+			},
+		},
+		"snapshot attributes configured as empty": {
+			prior: TargetResourceModel{
+				Snapshot: &targetSnapshotModel{
+					SrcURL: types.StringValue("postgres://localhost/tf_test"),
+					DstURL: types.StringValue(""),
+				},
+			},
+			response: dbsTargetModel.Target{Snapshot: dbsTargetModel.SnapshotCfg{SrcURL: "postgres://localhost/tf_test"}},
+			check: func(t *testing.T, got *TargetResourceModel) {
+				if !got.Snapshot.DstURL.Equal(types.StringValue("")) {
+					t.Errorf("snapshot.dst_url = %s, want empty string", got.Snapshot.DstURL)
+				}
+			},
+		},
+		"imported target": {
+			response: dbsTargetModel.Target{
+				Snapshot: dbsTargetModel.SnapshotCfg{SrcURL: "postgres://localhost/tf_test"},
+				Sanitize: dbsTargetModel.SanitizeCfg{Query: "UPDATE users SET email = NULL;"},
+			},
+			check: func(t *testing.T, got *TargetResourceModel) {
+				if !got.Snapshot.DstURL.IsNull() {
+					t.Errorf("snapshot.dst_url = %s, want null", got.Snapshot.DstURL)
+				}
+				if got.Sanitize == nil {
+					t.Fatal("sanitize = nil, want the sanitize block")
+				}
+				if !got.Sanitize.DstURL.IsNull() {
+					t.Errorf("sanitize.dst_url = %s, want null", got.Sanitize.DstURL)
+				}
+			},
+		},
+		"sanitize dst_url configured as empty": {
+			prior: TargetResourceModel{
+				Sanitize: &targetSanitizeModel{
+					DstURL: types.StringValue(""),
+					Query:  types.StringValue("UPDATE users SET email = NULL;"),
+				},
+			},
+			response: dbsTargetModel.Target{Sanitize: dbsTargetModel.SanitizeCfg{Query: "UPDATE users SET email = NULL;"}},
+			check: func(t *testing.T, got *TargetResourceModel) {
+				if !got.Sanitize.DstURL.Equal(types.StringValue("")) {
+					t.Errorf("sanitize.dst_url = %s, want empty string", got.Sanitize.DstURL)
+				}
+			},
+		},
+		"share without sso_groups": {
+			prior: TargetResourceModel{
+				Share: &targetShareModel{SSOGroups: types.SetNull(types.StringType)},
+			},
+			response: dbsTargetModel.Target{Share: dbsTargetModel.ShareCfg{SsoGroups: []string{}}},
+			check: func(t *testing.T, got *TargetResourceModel) {
+				if got.Share == nil || !got.Share.SSOGroups.IsNull() {
+					t.Errorf("share = %+v, want the share block without sso_groups", got.Share)
+				}
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := APIResponseToResourceModel(ctx, &tc.response, &tc.prior)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			tc.check(t, got)
+		})
+	}
+}
@@ -15,12 +15,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure dbSnapperProvider satisfies various provider interfaces.
-var _ provider.Provider = &dbSnapperProvider{}
-
-const baseURLProduction = "https://app.dbsnapper.com/api/v3"
+var (
+	_ provider.Provider              = &dbSnapperProvider{}
+	_ provider.ProviderWithFunctions = &dbSnapperProvider{}
+)
 
 // dbSnapperProvider defines the provider implementation.
 type dbSnapperProvider struct {
@@ -91,7 +93,7 @@ func (p *dbSnapperProvider) Configure(ctx context.Context, req provider.Configur
 
 	// If the baseURL is not set, default to production
 	if baseURL == "" {
-		baseURL = baseURLProduction
+		baseURL = client.BaseURLProduction
 	}
 
 	if authtoken == "" {
@@ -111,9 +113,25 @@ func (p *dbSnapperProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	resp.DataSourceData = dbs
-	resp.ResourceData = dbs
+	// Log every API call with the context of the operation that makes it
+	api := client.NewInstrumentedAPI(dbs, logAPICall)
+
+	resp.DataSourceData = api
+	resp.ResourceData = api
+
+}
 
+// logAPICall logs a finished API call, with the fields of the resource or
+// data source operation in the context.
+func logAPICall(ctx context.Context, call client.Call) {
+	fields := map[string]interface{}{
+		"method":      call.Method,
+		"duration_ms": call.Duration.Milliseconds(),
+	}
+	if call.Err != nil {
+		fields["error"] = call.Err.Error()
+	}
+	tflog.Debug(ctx, "DBSnapper Provider: API call", fields)
 }
 
 type Resourcer interface {
@@ -124,6 +142,7 @@ func (p *dbSnapperProvider) Resources(ctx context.Context) []func() resource.Res
 	return []func() resource.Resource{
 		NewTargetResource,
 		NewStorageProviderResource,
+		NewTargetShareResource,
 	}
 }
 
@@ -134,7 +153,13 @@ func (p *dbSnapperProvider) DataSources(ctx context.Context) []func() datasource
 }
 
 func (p *dbSnapperProvider) Functions(ctx context.Context) []func() function.Function {
-	return nil
+	return []func() function.Function{
+		NewParseDBURLFunction,
+		NewRedactDBURLFunction,
+		NewBuildDBURLFunction,
+		NewSanitizeSQLFunction,
+		NewSnapshotURIFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
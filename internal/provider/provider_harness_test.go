@@ -0,0 +1,412 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// providerHarness drives the provider server through the protocol calls
+// Terraform makes for plan, apply, refresh, import and destroy, so that the
+// lifecycle of the resources runs in plain go test against a fakeAPI, without
+// the Terraform CLI or network access.
+type providerHarness struct {
+	t       *testing.T
+	server  tfprotov6.ProviderServer
+	schemas *tfprotov6.GetProviderSchemaResponse
+}
+
+// newProviderHarness returns a harness with the provider configured to use the fake API.
+func newProviderHarness(t *testing.T, api *fakeAPI) *providerHarness {
+	t.Helper()
+	ctx := context.Background()
+
+	// Poll the fake API without waiting
+	interval := pollInterval
+	pollInterval = time.Millisecond
+	t.Cleanup(func() { pollInterval = interval })
+
+	server, err := testAccProtoV6ProviderFactories["dbsnapper"]()
+	if err != nil {
+		t.Fatalf("unable to create provider server: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+
+	h := &providerHarness{t: t, server: server, schemas: schemas}
+
+	config := h.value(schemas.Provider, fmt.Sprintf(`{"authtoken": %q, "base_url": %q}`, fakeAPIToken, api.URL))
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.10.0",
+		Config:           h.dynamicValue(schemas.Provider, config),
+	})
+	if err != nil {
+		t.Fatalf("unable to configure provider: %s", err)
+	}
+	requireNoErrors(t, resp.Diagnostics)
+
+	return h
+}
+
+// resourceSchema returns the schema of the resource type.
+func (h *providerHarness) resourceSchema(typeName string) *tfprotov6.Schema {
+	h.t.Helper()
+	s, ok := h.schemas.ResourceSchemas[typeName]
+	if !ok {
+		h.t.Fatalf("no schema for resource %s", typeName)
+	}
+	return s
+}
+
+// value decodes the JSON object into a value of the schema, attributes that
+// are left out are null.
+func (h *providerHarness) value(schema *tfprotov6.Schema, rawJSON string) tftypes.Value {
+	h.t.Helper()
+	v, err := (&tfprotov6.RawState{JSON: []byte(rawJSON)}).Unmarshal(schema.ValueType())
+	if err != nil {
+		h.t.Fatalf("unable to decode %s: %s", rawJSON, err)
+	}
+	return v
+}
+
+// config decodes the JSON configuration of the resource type.
+func (h *providerHarness) config(typeName, rawJSON string) tftypes.Value {
+	h.t.Helper()
+	return h.value(h.resourceSchema(typeName), rawJSON)
+}
+
+// dynamicValue encodes the value of the schema.
+func (h *providerHarness) dynamicValue(schema *tfprotov6.Schema, v tftypes.Value) *tfprotov6.DynamicValue {
+	h.t.Helper()
+	dv, err := tfprotov6.NewDynamicValue(schema.ValueType(), v)
+	if err != nil {
+		h.t.Fatalf("unable to encode value: %s", err)
+	}
+	return &dv
+}
+
+// decode decodes the dynamic value of the schema, nil decodes to null.
+func (h *providerHarness) decode(schema *tfprotov6.Schema, dv *tfprotov6.DynamicValue) tftypes.Value {
+	h.t.Helper()
+	if dv == nil {
+		return tftypes.NewValue(schema.ValueType(), nil)
+	}
+	v, err := dv.Unmarshal(schema.ValueType())
+	if err != nil {
+		h.t.Fatalf("unable to decode value: %s", err)
+	}
+	return v
+}
+
+// plan plans the change of the prior state to the configuration.
+func (h *providerHarness) plan(typeName string, prior, config tftypes.Value) (*tfprotov6.PlanResourceChangeResponse, tftypes.Value) {
+	h.t.Helper()
+	ctx := context.Background()
+	schema := h.resourceSchema(typeName)
+
+	if !config.IsNull() {
+		validateResp, err := h.server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+			TypeName: typeName,
+			Config:   h.dynamicValue(schema, config),
+		})
+		if err != nil {
+			h.t.Fatalf("unable to validate %s: %s", typeName, err)
+		}
+		if hasErrors(validateResp.Diagnostics) {
+			return &tfprotov6.PlanResourceChangeResponse{Diagnostics: validateResp.Diagnostics}, tftypes.Value{}
+		}
+	}
+
+	resp, err := h.server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       h.dynamicValue(schema, prior),
+		ProposedNewState: h.dynamicValue(schema, proposedNewState(schema, prior, config)),
+		Config:           h.dynamicValue(schema, config),
+	})
+	if err != nil {
+		h.t.Fatalf("unable to plan %s: %s", typeName, err)
+	}
+	return resp, h.decode(schema, resp.PlannedState)
+}
+
+// apply plans and applies the change of the prior state to the
+// configuration, a null configuration destroys the resource. Like Terraform,
+// it replaces the resource when the plan requires it, and reports a new
+// state that differs from the plan as an error diagnostic.
+func (h *providerHarness) apply(typeName string, prior, config tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+	ctx := context.Background()
+	schema := h.resourceSchema(typeName)
+
+	planResp, planned := h.plan(typeName, prior, config)
+	if hasErrors(planResp.Diagnostics) {
+		return prior, planResp.Diagnostics
+	}
+
+	if len(planResp.RequiresReplace) > 0 && !prior.IsNull() && !config.IsNull() {
+		if destroyed, diags := h.apply(typeName, prior, tftypes.NewValue(schema.ValueType(), nil)); hasErrors(diags) {
+			return destroyed, diags
+		}
+		return h.apply(typeName, tftypes.NewValue(schema.ValueType(), nil), config)
+	}
+
+	resp, err := h.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     h.dynamicValue(schema, prior),
+		PlannedState:   h.dynamicValue(schema, planned),
+		Config:         h.dynamicValue(schema, config),
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		h.t.Fatalf("unable to apply %s: %s", typeName, err)
+	}
+	state := h.decode(schema, resp.NewState)
+	if hasErrors(resp.Diagnostics) {
+		return state, resp.Diagnostics
+	}
+
+	if err := conformsToPlan(planned, state); err != nil {
+		return state, append(resp.Diagnostics, &tfprotov6.Diagnostic{
+			Severity: tfprotov6.DiagnosticSeverityError,
+			Summary:  "Provider produced inconsistent result after apply",
+			Detail:   err.Error(),
+		})
+	}
+	return state, resp.Diagnostics
+}
+
+// destroy destroys the resource of the state.
+func (h *providerHarness) destroy(typeName string, state tftypes.Value) []*tfprotov6.Diagnostic {
+	h.t.Helper()
+	_, diags := h.apply(typeName, state, tftypes.NewValue(h.resourceSchema(typeName).ValueType(), nil))
+	return diags
+}
+
+// read refreshes the state of the resource.
+func (h *providerHarness) read(typeName string, state tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+	schema := h.resourceSchema(typeName)
+
+	resp, err := h.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: h.dynamicValue(schema, state),
+	})
+	if err != nil {
+		h.t.Fatalf("unable to read %s: %s", typeName, err)
+	}
+	return h.decode(schema, resp.NewState), resp.Diagnostics
+}
+
+// importState imports the resource with the import identifier and refreshes it.
+func (h *providerHarness) importState(typeName, id string) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+	schema := h.resourceSchema(typeName)
+
+	resp, err := h.server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	})
+	if err != nil {
+		h.t.Fatalf("unable to import %s: %s", typeName, err)
+	}
+	if hasErrors(resp.Diagnostics) {
+		return tftypes.NewValue(schema.ValueType(), nil), resp.Diagnostics
+	}
+	if len(resp.ImportedResources) != 1 {
+		h.t.Fatalf("imported %d resources, want 1", len(resp.ImportedResources))
+	}
+	return h.read(typeName, h.decode(schema, resp.ImportedResources[0].State))
+}
+
+// readDataSource reads the data source with the JSON configuration.
+func (h *providerHarness) readDataSource(typeName, rawJSON string) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+	schema, ok := h.schemas.DataSourceSchemas[typeName]
+	if !ok {
+		h.t.Fatalf("no schema for data source %s", typeName)
+	}
+
+	resp, err := h.server.ReadDataSource(context.Background(), &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   h.dynamicValue(schema, h.value(schema, rawJSON)),
+	})
+	if err != nil {
+		h.t.Fatalf("unable to read data source %s: %s", typeName, err)
+	}
+	return h.decode(schema, resp.State), resp.Diagnostics
+}
+
+// requireNoChanges fails the test when planning the configuration against the
+// state is not empty.
+func (h *providerHarness) requireNoChanges(typeName string, state, config tftypes.Value) {
+	h.t.Helper()
+	resp, planned := h.plan(typeName, state, config)
+	requireNoErrors(h.t, resp.Diagnostics)
+	if !planned.Equal(state) {
+		h.t.Fatalf("expected an empty plan, planned\n%s\nstate\n%s", planned, state)
+	}
+}
+
+// proposedNewState merges the configuration and the prior state like
+// Terraform does for PlanResourceChange: the computed attributes that are
+// null in the configuration keep their prior value.
+func proposedNewState(schema *tfprotov6.Schema, prior, config tftypes.Value) tftypes.Value {
+	if prior.IsNull() || config.IsNull() {
+		return config
+	}
+
+	computed := make(map[string]bool)
+	collectComputedAttributes(schema.Block.Attributes, "", computed)
+
+	v, err := tftypes.Transform(config, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsNull() || !computed[attributeName(p)] {
+			return v, nil
+		}
+		priorValue, _, err := tftypes.WalkAttributePath(prior, p)
+		if err != nil {
+			return v, nil
+		}
+		return priorValue.(tftypes.Value), nil
+	})
+	if err != nil {
+		return config
+	}
+	return v
+}
+
+// collectComputedAttributes adds the dotted names of the computed attributes to computed.
+func collectComputedAttributes(attributes []*tfprotov6.SchemaAttribute, prefix string, computed map[string]bool) {
+	for _, a := range attributes {
+		name := prefix + a.Name
+		if a.Computed {
+			computed[name] = true
+		}
+		if a.NestedType != nil {
+			collectComputedAttributes(a.NestedType.Attributes, name+".", computed)
+		}
+	}
+}
+
+// generatedConfig returns the configuration Terraform writes for the state
+// with plan -generate-config-out: the computed attributes that cannot be
+// configured are left out, and sensitive values are written as null.
+func (h *providerHarness) generatedConfig(typeName string, state tftypes.Value) tftypes.Value {
+	h.t.Helper()
+	omitted := make(map[string]bool)
+	collectGeneratedConfigOmissions(h.resourceSchema(typeName).Block.Attributes, "", omitted)
+
+	v, err := tftypes.Transform(state, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if omitted[attributeName(p)] {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		h.t.Fatalf("unable to generate the configuration of %s: %s", typeName, err)
+	}
+	return v
+}
+
+// collectGeneratedConfigOmissions adds the dotted names of the attributes
+// that generated configuration leaves out to omitted.
+func collectGeneratedConfigOmissions(attributes []*tfprotov6.SchemaAttribute, prefix string, omitted map[string]bool) {
+	for _, a := range attributes {
+		name := prefix + a.Name
+		if (a.Computed && !a.Optional) || a.Sensitive {
+			omitted[name] = true
+		}
+		if a.NestedType != nil {
+			collectGeneratedConfigOmissions(a.NestedType.Attributes, name+".", omitted)
+		}
+	}
+}
+
+// attributeName returns the dotted name of the attribute at the path, without the element keys.
+func attributeName(p *tftypes.AttributePath) string {
+	var names []string
+	for _, step := range p.Steps() {
+		if name, ok := step.(tftypes.AttributeName); ok {
+			names = append(names, string(name))
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// conformsToPlan reports the values of the new state that differ from the
+// known values of the planned state, and unknown values in the new state.
+func conformsToPlan(planned, state tftypes.Value) error {
+	if !state.IsFullyKnown() {
+		return fmt.Errorf("the new state has unknown values:\n%s", state)
+	}
+
+	var diffs []string
+	err := tftypes.Walk(planned, func(p *tftypes.AttributePath, plannedValue tftypes.Value) (bool, error) {
+		if !plannedValue.IsFullyKnown() {
+			return true, nil
+		}
+		stateValue, _, err := tftypes.WalkAttributePath(state, p)
+		if err != nil || !plannedValue.Equal(stateValue.(tftypes.Value)) {
+			diffs = append(diffs, fmt.Sprintf("%s: planned %s, got %v", p, plannedValue, stateValue))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// withoutAttributes returns the value with the top-level attributes set to null.
+func withoutAttributes(v tftypes.Value, names ...string) tftypes.Value {
+	v, _ = tftypes.Transform(v, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		for _, name := range names {
+			if p.Equal(tftypes.NewAttributePath().WithAttributeName(name)) {
+				return tftypes.NewValue(v.Type(), nil), nil
+			}
+		}
+		return v, nil
+	})
+	return v
+}
+
+// hasErrors reports whether the diagnostics contain an error.
+func hasErrors(diags []*tfprotov6.Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// requireNoErrors fails the test when the diagnostics contain an error.
+func requireNoErrors(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+// requireError fails the test unless the diagnostics contain an error with
+// the summary, whose detail contains detail.
+func requireError(t *testing.T, diags []*tfprotov6.Diagnostic, summary, detail string) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError && d.Summary == summary && strings.Contains(d.Detail, detail) {
+			return
+		}
+	}
+	t.Fatalf("expected an error %q containing %q, got %v", summary, detail, diags)
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sanitizeRule sanitizes a column of a table with one of the sanitizeStrategies.
+type sanitizeRule struct {
+	Table    string // optionally schema qualified, e.g. public.users, see splitQualifiedIdentifier
+	Column   string
+	Strategy string
+}
+
+// sanitizeStrategies maps each sanitization strategy onto the expression that
+// replaces the column value, by engine. %s is the quoted column name.
+var sanitizeStrategies = map[string]map[string]string{
+	// null sets the column to NULL
+	"null": {
+		dbEnginePostgres: "NULL",
+		dbEngineMySQL:    "NULL",
+	},
+	// empty sets the column to an empty string
+	"empty": {
+		dbEnginePostgres: "''",
+		dbEngineMySQL:    "''",
+	},
+	// redact sets the column to the fixed string REDACTED
+	"redact": {
+		dbEnginePostgres: "'REDACTED'",
+		dbEngineMySQL:    "'REDACTED'",
+	},
+	// mask replaces every character of the column with *
+	"mask": {
+		dbEnginePostgres: "repeat('*', length(%s::text))",
+		dbEngineMySQL:    "REPEAT('*', CHAR_LENGTH(%s))",
+	},
+	// hash replaces the column with its MD5 hash, keeping equal values equal
+	"hash": {
+		dbEnginePostgres: "md5(%s::text)",
+		dbEngineMySQL:    "MD5(%s)",
+	},
+	// email replaces the column with an address at example.com that is unique per value
+	"email": {
+		dbEnginePostgres: "md5(%s::text) || '@example.com'",
+		dbEngineMySQL:    "CONCAT(MD5(%s), '@example.com')",
+	},
+}
+
+// sanitizeStrategyNames returns the names of the sanitization strategies, sorted.
+func sanitizeStrategyNames() []string {
+	names := make([]string, 0, len(sanitizeStrategies))
+	for name := range sanitizeStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compileSanitizeSQL compiles the rules into the sanitization query of a
+// target, an UPDATE statement per table in the order the tables first appear
+// in the rules. The engine is a database URL scheme, e.g. postgres or mysql.
+func compileSanitizeSQL(engine string, rules []sanitizeRule) (string, error) {
+	dbEngine, ok := dbEngines[strings.ToLower(engine)]
+	if !ok {
+		return "", fmt.Errorf("unsupported database engine %q, expected one of: %s", engine, strings.Join(dbURLSchemes(), ", "))
+	}
+
+	var tables []string
+	assignments := make(map[string][]string)
+	columns := make(map[string]bool)
+
+	for i, rule := range rules {
+		if rule.Table == "" || rule.Column == "" {
+			return "", fmt.Errorf("rule %d: the table and the column must be set", i)
+		}
+		expressions, ok := sanitizeStrategies[rule.Strategy]
+		if !ok {
+			return "", fmt.Errorf("rule %d: unsupported strategy %q, expected one of: %s", i, rule.Strategy, strings.Join(sanitizeStrategyNames(), ", "))
+		}
+
+		table, err := splitQualifiedIdentifier(rule.Table)
+		if err != nil {
+			return "", fmt.Errorf("rule %d: invalid table %q: %w", i, rule.Table, err)
+		}
+
+		quoted := quoteQualifiedIdentifier(dbEngine, table)
+		key := quoted + "\x00" + rule.Column
+		if columns[key] {
+			return "", fmt.Errorf("rule %d: the column %s of table %s is sanitized more than once", i, rule.Column, rule.Table)
+		}
+		columns[key] = true
+
+		column := quoteIdentifier(dbEngine, rule.Column)
+		expression := expressions[dbEngine]
+		if strings.Contains(expression, "%s") {
+			expression = fmt.Sprintf(expression, column)
+		}
+
+		if _, ok := assignments[quoted]; !ok {
+			tables = append(tables, quoted)
+		}
+		assignments[quoted] = append(assignments[quoted], column+" = "+expression)
+	}
+
+	var query strings.Builder
+	for _, table := range tables {
+		fmt.Fprintf(&query, "UPDATE %s SET %s;\n", table, strings.Join(assignments[table], ", "))
+	}
+	return query.String(), nil
+}
+
+// splitQualifiedIdentifier splits a dot separated identifier into its parts,
+// e.g. public.users into public and users. A part that contains a dot itself
+// must be quoted with double quotes or backticks, e.g. public."users.v2",
+// a quote inside a quoted part is escaped by doubling it.
+func splitQualifiedIdentifier(name string) ([]string, error) {
+	var parts []string
+	for rest := name; ; {
+		var part string
+		if rest != "" && (rest[0] == '"' || rest[0] == '`') {
+			quote := rest[:1]
+			var b strings.Builder
+			closed := false
+			for rest = rest[1:]; rest != ""; {
+				i := strings.Index(rest, quote)
+				if i < 0 {
+					break
+				}
+				b.WriteString(rest[:i])
+				rest = rest[i+1:]
+				if !strings.HasPrefix(rest, quote) {
+					closed = true
+					break
+				}
+				b.WriteString(quote)
+				rest = rest[1:]
+			}
+			if !closed {
+				return nil, fmt.Errorf("missing closing %s", quote)
+			}
+			if rest != "" && rest[0] != '.' {
+				return nil, fmt.Errorf("unexpected %q after quoted identifier", rest)
+			}
+			part = b.String()
+		} else {
+			i := strings.IndexByte(rest, '.')
+			if i < 0 {
+				i = len(rest)
+			}
+			part, rest = rest[:i], rest[i:]
+		}
+		if part == "" {
+			return nil, fmt.Errorf("empty identifier")
+		}
+		parts = append(parts, part)
+
+		if rest == "" {
+			return parts, nil
+		}
+		rest = rest[1:]
+	}
+}
+
+// quoteQualifiedIdentifier quotes each part of an identifier, see
+// splitQualifiedIdentifier, and joins them with dots.
+func quoteQualifiedIdentifier(engine string, parts []string) string {
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = quoteIdentifier(engine, part)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// quoteIdentifier quotes an identifier for the engine, escaping the quote character.
+func quoteIdentifier(engine, name string) string {
+	quote := `"`
+	if engine == dbEngineMySQL {
+		quote = "`"
+	}
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the provider defined types fully satisfy framework interfaces.
+var _ function.Function = &sanitizeSQLFunction{}
+
+// sanitizeRuleModel maps a sanitization rule object.
+type sanitizeRuleModel struct {
+	Table    types.String `tfsdk:"table"`
+	Column   types.String `tfsdk:"column"`
+	Strategy types.String `tfsdk:"strategy"`
+}
+
+// NewSanitizeSQLFunction is a helper function to simplify the provider implementation.
+func NewSanitizeSQLFunction() function.Function {
+	return &sanitizeSQLFunction{}
+}
+
+// sanitizeSQLFunction defines the sanitize_sql function implementation.
+type sanitizeSQLFunction struct{}
+
+func (f *sanitizeSQLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sanitize_sql"
+}
+
+func (f *sanitizeSQLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compile sanitization rules into the SQL of sanitize.query",
+		MarkdownDescription: "Compiles a list of sanitization rules into the SQL for the `sanitize.query` of a `dbsnapper_target`, " +
+			"an `UPDATE` statement per table. Each rule is an object with the attributes `table` (optionally schema qualified, e.g. `public.users`, " +
+			"the name is split on dots, so a part that contains a dot must be quoted, e.g. `public.\"users.v2\"`), " +
+			"`column` and `strategy`, one of: `" + strings.Join(sanitizeStrategyNames(), "`, `") + "`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "engine",
+				Description: "The database engine the SQL is written for, postgres or mysql",
+			},
+			function.ListParameter{
+				Name:        "rules",
+				Description: "The sanitization rules",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"table":    types.StringType,
+						"column":   types.StringType,
+						"strategy": types.StringType,
+					},
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *sanitizeSQLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var engine string
+	var ruleModels []sanitizeRuleModel
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &engine, &ruleModels))
+	if resp.Error != nil {
+		return
+	}
+
+	rules := make([]sanitizeRule, len(ruleModels))
+	for i, rule := range ruleModels {
+		rules[i] = sanitizeRule{
+			Table:    rule.Table.ValueString(),
+			Column:   rule.Column.ValueString(),
+			Strategy: rule.Strategy.ValueString(),
+		}
+	}
+
+	query, err := compileSanitizeSQL(engine, rules)
+	if err != nil {
+		// Errors about the engine are on the first argument, errors about a rule on the second
+		argument := int64(1)
+		if _, ok := dbEngines[strings.ToLower(engine)]; !ok {
+			argument = 0
+		}
+		resp.Error = function.NewArgumentFuncError(argument, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, query))
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccSanitizeSQLFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider functions are available from Terraform 1.8
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSanitizeSQLFunctionConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("query", "UPDATE \"users\" SET \"email\" = md5(\"email\"::text) || '@example.com', \"phone\" = NULL;\n"),
+				),
+			},
+			{
+				Config: `output "query" {
+  value = provider::dbsnapper::sanitize_sql("postgres", [{ table = "users", column = "email", strategy = "shuffle" }])
+}`,
+				ExpectError: regexp.MustCompile(`unsupported strategy "shuffle"`),
+			},
+		},
+	})
+}
+
+const testAccSanitizeSQLFunctionConfig = `
+output "query" {
+  value = provider::dbsnapper::sanitize_sql("postgres", [
+    { table = "users", column = "email", strategy = "email" },
+    { table = "users", column = "phone", strategy = "null" },
+  ])
+}
+`
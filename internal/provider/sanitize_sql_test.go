@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileSanitizeSQL(t *testing.T) {
+	testCases := map[string]struct {
+		engine  string
+		rules   []sanitizeRule
+		want    string
+		wantErr string
+	}{
+		"postgres": {
+			engine: "postgres",
+			rules: []sanitizeRule{
+				{Table: "public.users", Column: "email", Strategy: "email"},
+				{Table: "orders", Column: "card_number", Strategy: "mask"},
+				{Table: "public.users", Column: "name", Strategy: "redact"},
+				{Table: "public.users", Column: "phone", Strategy: "null"},
+			},
+			want: `UPDATE "public"."users" SET "email" = md5("email"::text) || '@example.com', "name" = 'REDACTED', "phone" = NULL;` + "\n" +
+				`UPDATE "orders" SET "card_number" = repeat('*', length("card_number"::text));` + "\n",
+		},
+		"mysql alias": {
+			engine: "mariadb",
+			rules: []sanitizeRule{
+				{Table: "users", Column: "ssn", Strategy: "hash"},
+				{Table: "users", Column: "notes", Strategy: "empty"},
+			},
+			want: "UPDATE `users` SET `ssn` = MD5(`ssn`), `notes` = '';\n",
+		},
+		"quoted identifiers": {
+			engine: "postgresql",
+			rules:  []sanitizeRule{{Table: `odd"table`, Column: `odd"column`, Strategy: "null"}},
+			want:   `UPDATE "odd""table" SET "odd""column" = NULL;` + "\n",
+		},
+		"quoted table with a dot": {
+			engine: "postgres",
+			rules: []sanitizeRule{
+				{Table: `public."users.v2"`, Column: "email", Strategy: "null"},
+				{Table: `"public"."users.v2"`, Column: "name", Strategy: "null"},
+			},
+			want: `UPDATE "public"."users.v2" SET "email" = NULL, "name" = NULL;` + "\n",
+		},
+		"backtick quoted table": {
+			engine: "mysql",
+			rules:  []sanitizeRule{{Table: "app.`users.v2`", Column: "email", Strategy: "null"}},
+			want:   "UPDATE `app`.`users.v2` SET `email` = NULL;\n",
+		},
+		"invalid table": {
+			engine:  "postgres",
+			rules:   []sanitizeRule{{Table: "public..users", Column: "email", Strategy: "null"}},
+			wantErr: `rule 0: invalid table "public..users": empty identifier`,
+		},
+		"no rules": {
+			engine: "postgres",
+			want:   "",
+		},
+		"unsupported engine": {
+			engine:  "sqlite",
+			wantErr: `unsupported database engine "sqlite"`,
+		},
+		"unsupported strategy": {
+			engine:  "postgres",
+			rules:   []sanitizeRule{{Table: "users", Column: "email", Strategy: "shuffle"}},
+			wantErr: `rule 0: unsupported strategy "shuffle", expected one of: email, empty, hash, mask, null, redact`,
+		},
+		"missing column": {
+			engine:  "postgres",
+			rules:   []sanitizeRule{{Table: "users", Strategy: "null"}},
+			wantErr: "rule 0: the table and the column must be set",
+		},
+		"duplicate column": {
+			engine: "mysql",
+			rules: []sanitizeRule{
+				{Table: "users", Column: "email", Strategy: "null"},
+				{Table: "users", Column: "email", Strategy: "hash"},
+			},
+			wantErr: "rule 1: the column email of table users is sanitized more than once",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := compileSanitizeSQL(tc.engine, tc.rules)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("compileSanitizeSQL() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("compileSanitizeSQL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitQualifiedIdentifier(t *testing.T) {
+	testCases := map[string]struct {
+		name    string
+		want    []string
+		wantErr string
+	}{
+		"table":             {name: "users", want: []string{"users"}},
+		"schema qualified":  {name: "public.users", want: []string{"public", "users"}},
+		"quoted with a dot": {name: `public."users.v2"`, want: []string{"public", "users.v2"}},
+		"escaped quote":     {name: `"odd""name"`, want: []string{`odd"name`}},
+		"backticks":         {name: "`app`.`users.v2`", want: []string{"app", "users.v2"}},
+		"unquoted quote":    {name: `odd"table`, want: []string{`odd"table`}},
+		"empty":             {name: "", wantErr: "empty identifier"},
+		"trailing dot":      {name: "public.", wantErr: "empty identifier"},
+		"unclosed quote":    {name: `public."users`, wantErr: `missing closing "`},
+		"text after quote":  {name: `"public"x.users`, wantErr: `unexpected "x.users" after quoted identifier`},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := splitQualifiedIdentifier(tc.name)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("splitQualifiedIdentifier(%q) error = %v, want %q", tc.name, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Join(got, "\x00") != strings.Join(tc.want, "\x00") {
+				t.Errorf("splitQualifiedIdentifier(%q) = %q, want %q", tc.name, got, tc.want)
+			}
+		})
+	}
+}
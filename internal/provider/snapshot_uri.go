@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	storageProviderS3 = "s3"
+	storageProviderR2 = "r2"
+
+	// r2EndpointFormat is the S3 compatible endpoint of a Cloudflare account.
+	r2EndpointFormat = "https://%s.r2.cloudflarestorage.com"
+)
+
+// snapshotLocation is where DBSnapper stores the snapshot of a target taken
+// at a given time, and its sanitized copy.
+type snapshotLocation struct {
+	Bucket       string
+	Prefix       string // empty or ending in /
+	Key          string
+	SanitizedKey string
+	URI          string
+	SanitizedURI string
+}
+
+// storageLocation holds the storage profile settings the location of a
+// snapshot depends on.
+type storageLocation struct {
+	Provider  string
+	AccountID string
+	Bucket    string
+	Prefix    string
+}
+
+// snapshotObjectKeys returns the object keys of the snapshot and its sanitized
+// copy. DBSnapper names snapshots <unix timestamp>_<target name>.zip, and the
+// sanitized copy <unix timestamp>_<target name>.san.zip, below the prefix of
+// the storage profile.
+func snapshotObjectKeys(prefix, targetName string, takenAt time.Time) (key, sanitizedKey string) {
+	base := normalizeStoragePrefix(prefix) + fmt.Sprintf("%d_%s", takenAt.Unix(), targetName)
+	return base + ".zip", base + ".san.zip"
+}
+
+// normalizeStoragePrefix returns the prefix without leading slashes and with a
+// single trailing slash, or an empty string when there is no prefix.
+func normalizeStoragePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// storageObjectURI returns the URI of an object: s3://bucket/key for S3 and the
+// URL at the account endpoint for Cloudflare R2.
+func storageObjectURI(location storageLocation, key string) (string, error) {
+	switch strings.ToLower(location.Provider) {
+	case storageProviderS3:
+		return fmt.Sprintf("s3://%s/%s", location.Bucket, key), nil
+	case storageProviderR2:
+		if location.AccountID == "" {
+			return "", fmt.Errorf("the account_id of the storage profile is required for the r2 provider")
+		}
+		return fmt.Sprintf(r2EndpointFormat+"/%s/%s", location.AccountID, location.Bucket, key), nil
+	default:
+		return "", fmt.Errorf("unsupported storage provider %q, expected one of: %s, %s", location.Provider, storageProviderS3, storageProviderR2)
+	}
+}
+
+// snapshotURI returns the location of the snapshot of the target taken at takenAt.
+func snapshotURI(location storageLocation, targetName string, takenAt time.Time) (*snapshotLocation, error) {
+	if location.Bucket == "" {
+		return nil, fmt.Errorf("the bucket of the storage profile must be set")
+	}
+	if targetName == "" {
+		return nil, fmt.Errorf("the target name must be set")
+	}
+
+	key, sanitizedKey := snapshotObjectKeys(location.Prefix, targetName, takenAt)
+
+	uri, err := storageObjectURI(location, key)
+	if err != nil {
+		return nil, err
+	}
+	sanitizedURI, err := storageObjectURI(location, sanitizedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotLocation{
+		Bucket:       location.Bucket,
+		Prefix:       normalizeStoragePrefix(location.Prefix),
+		Key:          key,
+		SanitizedKey: sanitizedKey,
+		URI:          uri,
+		SanitizedURI: sanitizedURI,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the provider defined types fully satisfy framework interfaces.
+var _ function.Function = &snapshotURIFunction{}
+
+// snapshotURIStorageProfileModel maps the storage profile argument, the
+// attributes of dbsnapper_storage_profile that the location depends on.
+type snapshotURIStorageProfileModel struct {
+	Provider  types.String `tfsdk:"sp_provider"`
+	AccountID types.String `tfsdk:"account_id"`
+	Bucket    types.String `tfsdk:"bucket"`
+	Prefix    types.String `tfsdk:"prefix"`
+}
+
+// snapshotURIModel maps the result of snapshot_uri.
+type snapshotURIModel struct {
+	Bucket       types.String `tfsdk:"bucket"`
+	Prefix       types.String `tfsdk:"prefix"`
+	Key          types.String `tfsdk:"key"`
+	SanitizedKey types.String `tfsdk:"sanitized_key"`
+	URI          types.String `tfsdk:"uri"`
+	SanitizedURI types.String `tfsdk:"sanitized_uri"`
+}
+
+// NewSnapshotURIFunction is a helper function to simplify the provider implementation.
+func NewSnapshotURIFunction() function.Function {
+	return &snapshotURIFunction{}
+}
+
+// snapshotURIFunction defines the snapshot_uri function implementation.
+type snapshotURIFunction struct{}
+
+func (f *snapshotURIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "snapshot_uri"
+}
+
+func (f *snapshotURIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compute where DBSnapper stores the snapshot of a target",
+		MarkdownDescription: "Computes the bucket, prefix and object keys where DBSnapper stores the snapshot of a target taken at the timestamp, " +
+			"and its sanitized copy. Snapshots are stored as `<prefix>/<unix timestamp>_<target name>.zip`, sanitized copies as " +
+			"`<prefix>/<unix timestamp>_<target name>.san.zip`. The URIs are `s3://<bucket>/<key>` for the `s3` provider and " +
+			"`https://<account_id>.r2.cloudflarestorage.com/<bucket>/<key>` for the `r2` provider.\n\n" +
+			"The storage profile is an object with the attributes `sp_provider`, `account_id`, `bucket` and `prefix`, `account_id` and `prefix` may be null. " +
+			"A `dbsnapper_storage_profile` resource can be passed as well, but as its keys are sensitive the result is then sensitive too.",
+		Parameters: []function.Parameter{
+			function.ObjectParameter{
+				Name:        "storage_profile",
+				Description: "The storage profile the snapshot is stored with",
+				AttributeTypes: map[string]attr.Type{
+					"sp_provider": types.StringType,
+					"account_id":  types.StringType,
+					"bucket":      types.StringType,
+					"prefix":      types.StringType,
+				},
+			},
+			function.StringParameter{
+				Name:        "target_name",
+				Description: "The name of the target",
+			},
+			function.StringParameter{
+				Name:        "timestamp",
+				Description: "The time the snapshot was taken, as an RFC3339 timestamp",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"bucket":        types.StringType,
+				"prefix":        types.StringType,
+				"key":           types.StringType,
+				"sanitized_key": types.StringType,
+				"uri":           types.StringType,
+				"sanitized_uri": types.StringType,
+			},
+		},
+	}
+}
+
+func (f *snapshotURIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var storageProfile snapshotURIStorageProfileModel
+	var targetName, timestamp string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &storageProfile, &targetName, &timestamp))
+	if resp.Error != nil {
+		return
+	}
+
+	takenAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("The value %q is not a valid RFC3339 timestamp: %s", timestamp, err))
+		return
+	}
+	if targetName == "" {
+		resp.Error = function.NewArgumentFuncError(1, "the target name must be set")
+		return
+	}
+
+	location, err := snapshotURI(storageLocation{
+		Provider:  storageProfile.Provider.ValueString(),
+		AccountID: storageProfile.AccountID.ValueString(),
+		Bucket:    storageProfile.Bucket.ValueString(),
+		Prefix:    storageProfile.Prefix.ValueString(),
+	}, targetName, takenAt)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, snapshotURIModel{
+		Bucket:       types.StringValue(location.Bucket),
+		Prefix:       types.StringValue(location.Prefix),
+		Key:          types.StringValue(location.Key),
+		SanitizedKey: types.StringValue(location.SanitizedKey),
+		URI:          types.StringValue(location.URI),
+		SanitizedURI: types.StringValue(location.SanitizedURI),
+	}))
+}
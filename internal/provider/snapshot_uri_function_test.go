@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccSnapshotURIFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider functions are available from Terraform 1.8
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSnapshotURIFunctionConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("s3_uri", "s3://snapshots/prod/1717207200_orders.zip"),
+					resource.TestCheckOutput("r2_sanitized_uri", "https://abc123.r2.cloudflarestorage.com/snapshots/1717207200_orders.san.zip"),
+				),
+			},
+			{
+				Config: `output "uri" {
+  value = provider::dbsnapper::snapshot_uri({ sp_provider = "s3", account_id = null, bucket = "snapshots", prefix = null }, "orders", "yesterday")
+}`,
+				ExpectError: regexp.MustCompile(`not a valid RFC3339 timestamp`),
+			},
+		},
+	})
+}
+
+const testAccSnapshotURIFunctionConfig = `
+output "s3_uri" {
+  value = provider::dbsnapper::snapshot_uri({
+    sp_provider = "s3"
+    account_id  = null
+    bucket      = "snapshots"
+    prefix      = "prod"
+  }, "orders", "2024-06-01T02:00:00Z").uri
+}
+
+output "r2_sanitized_uri" {
+  value = provider::dbsnapper::snapshot_uri({
+    sp_provider = "r2"
+    account_id  = "abc123"
+    bucket      = "snapshots"
+    prefix      = ""
+  }, "orders", "2024-06-01T02:00:00Z").sanitized_uri
+}
+`
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotURI(t *testing.T) {
+	takenAt := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		location storageLocation
+		want     *snapshotLocation
+		wantErr  string
+	}{
+		"s3": {
+			location: storageLocation{Provider: "s3", Bucket: "snapshots", Prefix: "prod"},
+			want: &snapshotLocation{
+				Bucket:       "snapshots",
+				Prefix:       "prod/",
+				Key:          "prod/1717207200_orders.zip",
+				SanitizedKey: "prod/1717207200_orders.san.zip",
+				URI:          "s3://snapshots/prod/1717207200_orders.zip",
+				SanitizedURI: "s3://snapshots/prod/1717207200_orders.san.zip",
+			},
+		},
+		"s3 without prefix": {
+			location: storageLocation{Provider: "S3", Bucket: "snapshots"},
+			want: &snapshotLocation{
+				Bucket:       "snapshots",
+				Key:          "1717207200_orders.zip",
+				SanitizedKey: "1717207200_orders.san.zip",
+				URI:          "s3://snapshots/1717207200_orders.zip",
+				SanitizedURI: "s3://snapshots/1717207200_orders.san.zip",
+			},
+		},
+		"r2 with slashes around the prefix": {
+			location: storageLocation{Provider: "r2", AccountID: "abc123", Bucket: "snapshots", Prefix: "/teams/data/"},
+			want: &snapshotLocation{
+				Bucket:       "snapshots",
+				Prefix:       "teams/data/",
+				Key:          "teams/data/1717207200_orders.zip",
+				SanitizedKey: "teams/data/1717207200_orders.san.zip",
+				URI:          "https://abc123.r2.cloudflarestorage.com/snapshots/teams/data/1717207200_orders.zip",
+				SanitizedURI: "https://abc123.r2.cloudflarestorage.com/snapshots/teams/data/1717207200_orders.san.zip",
+			},
+		},
+		"r2 without account": {
+			location: storageLocation{Provider: "r2", Bucket: "snapshots"},
+			wantErr:  "the account_id of the storage profile is required",
+		},
+		"unsupported provider": {
+			location: storageLocation{Provider: "gcs", Bucket: "snapshots"},
+			wantErr:  `unsupported storage provider "gcs"`,
+		},
+		"no bucket": {
+			location: storageLocation{Provider: "s3"},
+			wantErr:  "the bucket of the storage profile must be set",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := snapshotURI(tc.location, "orders", takenAt)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("snapshotURI() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("snapshotURI() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -41,12 +41,12 @@ func APIResponseToSPResourceModel(ctx context.Context, spApiResponse *storage.St
 	resourceModel.ID = types.StringValue(spApiResponse.ID.String())
 	resourceModel.Name = types.StringValue(spApiResponse.Name)
 	resourceModel.Provider = types.StringValue(spApiResponse.Provider)
-	resourceModel.Region = types.StringValue(spApiResponse.Region)
-	resourceModel.AccountID = types.StringValue(spApiResponse.AccountID)
+	resourceModel.Region = optionalString(resourceModel.Region, spApiResponse.Region)
+	resourceModel.AccountID = optionalString(resourceModel.AccountID, spApiResponse.AccountID)
 	resourceModel.AccessKey = types.StringValue(spApiResponse.AccessKey)
 	resourceModel.SecretKey = types.StringValue(spApiResponse.SecretKey)
 	resourceModel.Bucket = types.StringValue(spApiResponse.Bucket)
-	resourceModel.Prefix = types.StringValue(spApiResponse.Prefix)
+	resourceModel.Prefix = optionalString(resourceModel.Prefix, spApiResponse.Prefix)
 	resourceModel.Status = types.StringValue(spApiResponse.Status)
 	resourceModel.CreatedAt = types.StringValue(spApiResponse.CreatedAt)
 	resourceModel.UpdatedAt = types.StringValue(spApiResponse.UpdatedAt)
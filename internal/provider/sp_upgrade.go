@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// storageProfileSchemaVersion is the current version of the storage profile resource schema.
+//
+//   - Version 0 is the unversioned schema.
+//   - Version 1 has the same attributes and starts the versioned schema.
+const storageProfileSchemaVersion = 1
+
+// UpgradeState upgrades the storage profile state written by earlier versions of the schema.
+func (r *storageProfileResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: rawStateUpgrader("storage profile", 0, upgradeStorageProfileStateV0),
+	}
+}
+
+// upgradeStorageProfileStateV0 converts a version 0 storage profile state into
+// version 1. The attributes are unchanged, the state is only validated against
+// the current schema.
+func upgradeStorageProfileStateV0(state map[string]interface{}) error {
+	return nil
+}
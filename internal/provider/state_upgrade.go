@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// rawStateUpgrader returns a state upgrader that rewrites the raw JSON state
+// of a prior schema version with upgrade. The upgraded JSON is decoded with
+// the current schema, so attributes added since the prior version are filled
+// in as null and the upgraders do not need a copy of every prior schema.
+func rawStateUpgrader(resourceName string, fromVersion int64, upgrade func(state map[string]interface{}) error) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			upgraded, err := upgradeRawState(req.RawState.JSON, upgrade)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Upgrade State",
+					fmt.Sprintf("Unable to upgrade %s state from version %d, got error: %s", resourceName, fromVersion, err),
+				)
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+// upgradeRawState applies upgrade to the decoded raw JSON state.
+func upgradeRawState(rawState []byte, upgrade func(state map[string]interface{}) error) ([]byte, error) {
+	if len(rawState) == 0 {
+		return nil, fmt.Errorf("no JSON state to upgrade")
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(rawState, &state); err != nil {
+		return nil, fmt.Errorf("unable to decode state: %w", err)
+	}
+
+	if err := upgrade(state); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(state)
+}
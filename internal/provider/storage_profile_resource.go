@@ -15,13 +15,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/joescharf/dbsnapper/v2/storage"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &storageProfileResource{}
-	_ resource.ResourceWithConfigure   = &storageProfileResource{}
-	_ resource.ResourceWithImportState = &storageProfileResource{}
+	_ resource.Resource                 = &storageProfileResource{}
+	_ resource.ResourceWithConfigure    = &storageProfileResource{}
+	_ resource.ResourceWithImportState  = &storageProfileResource{}
+	_ resource.ResourceWithUpgradeState = &storageProfileResource{}
 )
 
 func NewStorageProviderResource() resource.Resource {
@@ -30,7 +32,7 @@ func NewStorageProviderResource() resource.Resource {
 
 // storageProfileResource defines the resource implementation.
 type storageProfileResource struct {
-	client *client.DBSnapper
+	client client.API
 }
 
 type StorageProfileResourceModel struct {
@@ -56,6 +58,7 @@ func (r *storageProfileResource) Metadata(ctx context.Context, req resource.Meta
 func (r *storageProfileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Storage Profile resource",
+		Version:             storageProfileSchemaVersion,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -121,12 +124,12 @@ func (r *storageProfileResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.DBSnapper)
+	client, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *DBSnapper, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -164,7 +167,7 @@ func (r *storageProfileResource) Create(ctx context.Context, req resource.Create
 	}
 
 	// Call API to create storage profile
-	targetResponse, err := r.client.API.CreateStorageProfile(spApiRequest)
+	targetResponse, err := r.client.CreateStorageProfile(ctx, spApiRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create storage profile, got error: %s", err))
 		return
@@ -197,7 +200,7 @@ func (r *storageProfileResource) Read(ctx context.Context, req resource.ReadRequ
 	}
 
 	// Call API to read the storage profile
-	targetResponse, err := r.client.API.GetStorageProfile(state.ID.ValueString())
+	targetResponse, err := r.client.GetStorageProfile(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read storage profile, got error: %s", err))
 		return
@@ -244,7 +247,7 @@ func (r *storageProfileResource) Update(ctx context.Context, req resource.Update
 	}
 
 	// Update storage profile via API
-	storageProfileResponse, err := r.client.API.UpdateStorageProfile(plan.ID.ValueString(), storageProfileApiRequest)
+	storageProfileResponse, err := r.client.UpdateStorageProfile(ctx, plan.ID.ValueString(), storageProfileApiRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update storage profile id: %s, got error: %s", plan.ID.ValueString(), err))
 		return
@@ -277,7 +280,7 @@ func (r *storageProfileResource) Delete(ctx context.Context, req resource.Delete
 	}
 
 	// Delete storage profile via API
-	err := r.client.API.DeleteStorageProfile(data.ID.ValueString())
+	err := r.client.DeleteStorageProfile(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete storage profile, got error: %s", err))
 		return
@@ -286,7 +289,30 @@ func (r *storageProfileResource) Delete(ctx context.Context, req resource.Delete
 
 ////////////////////////////// IMPORT STATE //////////////////////////////
 
-// ImportState imports the storage profile resource state.
+// ImportState imports the storage profile resource state, either by ID or by
+// name in the form name:<name>.
 func (r *storageProfileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	name, ok := importName(req.ID)
+	if !ok {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	// Call API to resolve the name
+	storageProfiles, err := r.client.GetStorageProfiles(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list storage profiles, got error: %s", err))
+		return
+	}
+
+	id, err := resolveImportName("storage profile", name, storageProfiles,
+		func(sp storage.StorageProfile) string { return sp.Name },
+		func(sp storage.StorageProfile) string { return sp.ID.String() },
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Import Storage Profile", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
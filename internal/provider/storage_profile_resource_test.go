@@ -6,6 +6,71 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
+func TestStorageProfileResourceLifecycle(t *testing.T) {
+	api := newFakeAPI(t)
+	h := newProviderHarness(t, api)
+	const typeName = "dbsnapper_storage_profile"
+
+	// Create, region, account_id and prefix are omitted
+	config := h.config(typeName, `{
+		"name": "tf_test_storage_profile",
+		"sp_provider": "s3",
+		"access_key": "access",
+		"secret_key": "secret",
+		"bucket": "snapshots"
+	}`)
+	state, diags := h.apply(typeName, h.config(typeName, `null`), config)
+	requireNoErrors(t, diags)
+	for _, name := range []string{"region", "account_id", "prefix"} {
+		if v := stateValue(t, state, name); !v.IsNull() {
+			t.Errorf("%s = %s, want null", name, v)
+		}
+	}
+	id := stateString(t, state, "id")
+	if _, ok := api.storageProfile(id); !ok {
+		t.Fatalf("storage profile %s was not created", id)
+	}
+	h.requireNoChanges(typeName, state, config)
+
+	// Refresh and import, by ID and by name
+	refreshed, diags := h.read(typeName, state)
+	requireNoErrors(t, diags)
+	h.requireNoChanges(typeName, refreshed, config)
+	for _, importID := range []string{id, "name:tf_test_storage_profile"} {
+		imported, diags := h.importState(typeName, importID)
+		requireNoErrors(t, diags)
+		if !imported.Equal(state) {
+			t.Errorf("import %s:\n%s\nwant\n%s", importID, imported, state)
+		}
+	}
+
+	// Update
+	config = h.config(typeName, `{
+		"name": "tf_test_storage_profile",
+		"sp_provider": "s3",
+		"region": "us-west-2",
+		"access_key": "access",
+		"secret_key": "secret",
+		"bucket": "snapshots",
+		"prefix": "prod"
+	}`)
+	state, diags = h.apply(typeName, state, config)
+	requireNoErrors(t, diags)
+	if sp, _ := api.storageProfile(id); sp.Region != "us-west-2" || sp.Prefix != "prod" {
+		t.Errorf("storage profile region = %q, prefix = %q, want the updated values", sp.Region, sp.Prefix)
+	}
+	if got := stateString(t, state, "id"); got != id {
+		t.Errorf("id = %s, want the storage profile to be updated in place", got)
+	}
+	h.requireNoChanges(typeName, state, config)
+
+	// Delete
+	requireNoErrors(t, h.destroy(typeName, state))
+	if n := api.storageProfileCount(); n != 0 {
+		t.Errorf("%d storage profiles left after destroy", n)
+	}
+}
+
 func TestAccStorageProfileResource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -26,6 +91,13 @@ func TestAccStorageProfileResource(t *testing.T) {
 				// Ignore attributes that might not exist during import
 				ImportStateVerifyIgnore: []string{"last_updated"},
 			},
+			// ImportState by name testing
+			{
+				ResourceName:      "dbsnapper_storage_profile.test",
+				ImportState:       true,
+				ImportStateId:     "name:tf_test_storage_profile",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccStorageProfileResourceConfigUpdate,
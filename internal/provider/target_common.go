@@ -13,19 +13,14 @@ import (
 
 func TFToResourceModel(ctx context.Context, tf *TargetResourceModel) (*TargetResourceModel, error) {
 
-	// Initialize pointer fields
+	// Initialize the required pointer fields, the optional ones are left nil
+	// when they are not configured
 	if tf.Snapshot == nil {
 		tf.Snapshot = new(targetSnapshotModel)
 	}
-	if tf.Share == nil {
-		tf.Share = new(targetShareModel)
-	}
-	if tf.Sanitize == nil {
-		tf.Sanitize = new(targetSanitizeModel)
-	}
 
-	// Read the list of SSOGroups from the Share attribute
-	if !tf.Share.SSOGroups.IsNull() {
+	// Read the set of SSOGroups from the Share attribute
+	if tf.Share != nil && !tf.Share.SSOGroups.IsNull() {
 		elements := make([]types.String, 0, len(tf.Share.SSOGroups.Elements()))
 		diags := tf.Share.SSOGroups.ElementsAs(ctx, &elements, false)
 		if diags.HasError() {
@@ -67,19 +62,16 @@ func ResourceModelToAPIRequest(ctx context.Context, resourceModel *TargetResourc
 		}
 	}
 
-	// Copy the optional Share fields
-	if resourceModel.Share != nil {
-		// Read from the plan using ElementsAs
-		elements := make([]types.String, 0, len(resourceModel.Share.SSOGroups.Elements()))
-		diag := resourceModel.Share.SSOGroups.ElementsAs(ctx, &elements, false)
-		if diag.HasError() {
-			return targetRequest, fmt.Errorf("Error reading SSOGroups: %s", diag)
-		}
-		// Copy the elements into the API request
-		targetRequest.Share.SsoGroups = make([]string, len(elements))
-		for i, v := range elements {
-			targetRequest.Share.SsoGroups[i] = v.ValueString()
+	// Copy the optional Share fields, unless they are managed externally
+	if resourceModel.Share != nil && !resourceModel.IgnoreShare.ValueBool() {
+		// Copy the SSO groups into the API request, an unset set results in
+		// an empty list of groups
+		ssoGroups, err := stringsFromSet(ctx, resourceModel.Share.SSOGroups)
+		if err != nil {
+			return targetRequest, fmt.Errorf("Error reading SSOGroups: %w", err)
 		}
+		targetRequest.Share.SsoGroups = make([]string, len(ssoGroups))
+		copy(targetRequest.Share.SsoGroups, ssoGroups)
 	}
 
 	uid, _ := uuid.Parse(resourceModel.ID.ValueString())
@@ -96,37 +88,134 @@ func ResourceModelToAPIRequest(ctx context.Context, resourceModel *TargetResourc
 }
 
 func APIResponseToResourceModel(ctx context.Context, targetApiResponse *dbsTargetModel.Target, resourceModel *TargetResourceModel) (*TargetResourceModel, error) {
+	if targetApiResponse == nil {
+		return resourceModel, fmt.Errorf("Error mapping target: the API returned no target")
+	}
+
 	resourceModel.ID = types.StringValue(targetApiResponse.ID.String())
 	resourceModel.Name = types.StringValue(targetApiResponse.Name)
 	resourceModel.Status = types.StringValue(targetApiResponse.Status)
 	resourceModel.Messages = types.StringValue(targetApiResponse.Messages)
 
 	// Snapshot
+	if resourceModel.Snapshot == nil {
+		resourceModel.Snapshot = &targetSnapshotModel{DstURL: types.StringNull()}
+	}
 	resourceModel.Snapshot.SrcURL = types.StringValue(targetApiResponse.Snapshot.SrcURL)
-	resourceModel.Snapshot.DstURL = types.StringValue(targetApiResponse.Snapshot.DstURL)
+	resourceModel.Snapshot.DstURL = optionalString(resourceModel.Snapshot.DstURL, targetApiResponse.Snapshot.DstURL)
 	resourceModel.Snapshot.SrcBytes = types.Int64Value(targetApiResponse.Snapshot.SrcBytes)
-	if targetApiResponse.Snapshot.StorageProfile != (storage.StorageProfile{}) {
-		resourceModel.Snapshot.StorageProfile.ID = types.StringValue(targetApiResponse.Snapshot.StorageProfile.ID.String())
+	resourceModel.Snapshot.StorageProfile = storageProfileAPIToModel(&targetApiResponse.Snapshot.StorageProfile, resourceModel.Snapshot.StorageProfile)
+
+	// Sanitize, the nested objects are only set when the API returns them or
+	// the plan or state has them, so that an imported target reads back the
+	// same as a configured one
+	resourceModel.Sanitize = sanitizeAPIToModel(targetApiResponse.Sanitize, resourceModel.Sanitize)
+
+	// Share, unless it is managed externally
+	var err error
+	if resourceModel.IgnoreShare.ValueBool() {
+		resourceModel.Share = nil
+	} else if resourceModel.Share, err = shareAPIToModel(ctx, targetApiResponse.Share, resourceModel.Share); err != nil {
+		return resourceModel, err
+	}
+	resourceModel.CreatedAt = types.StringValue(targetApiResponse.CreatedAt)
+	resourceModel.UpdatedAt = types.StringValue(targetApiResponse.UpdatedAt)
+
+	return resourceModel, nil
+}
+
+// storageProfileAPIToModel converts the API storage profile reference of a
+// snapshot or sanitize configuration into the Terraform model. It is nil when
+// the API returns no storage profile and the prior plan or state has none.
+func storageProfileAPIToModel(sp *storage.StorageProfile, prior *targetStorageProfileModel) *targetStorageProfileModel {
+	if sp == nil || sp.ID == uuid.Nil {
+		if prior == nil {
+			return nil
+		}
+		return &targetStorageProfileModel{ID: optionalString(prior.ID, "")}
+	}
+	return &targetStorageProfileModel{ID: types.StringValue(sp.ID.String())}
+}
+
+// sanitizeAPIToModel converts the API sanitize configuration into the
+// Terraform model. It is nil when the API returns an empty configuration and
+// the prior plan or state has no sanitize block.
+func sanitizeAPIToModel(sanitize dbsTargetModel.SanitizeCfg, prior *targetSanitizeModel) *targetSanitizeModel {
+	storageProfileSet := sanitize.StorageProfile != nil && sanitize.StorageProfile.ID != uuid.Nil
+	if prior == nil {
+		if sanitize.DstURL == "" && sanitize.Query == "" && !storageProfileSet {
+			return nil
+		}
+		prior = new(targetSanitizeModel)
 	}
 
-	// Sanitize
-	if targetApiResponse.Sanitize != (dbsTargetModel.SanitizeCfg{}) {
-		resourceModel.Sanitize.DstURL = types.StringValue(targetApiResponse.Sanitize.DstURL)
-		resourceModel.Sanitize.Query = types.StringValue(targetApiResponse.Sanitize.Query)
-		if targetApiResponse.Sanitize.StorageProfile != nil {
-			resourceModel.Sanitize.StorageProfile.ID = types.StringValue(targetApiResponse.Sanitize.StorageProfile.ID.String())
+	return &targetSanitizeModel{
+		DstURL:         optionalString(prior.DstURL, sanitize.DstURL),
+		Query:          optionalString(prior.Query, sanitize.Query),
+		StorageProfile: storageProfileAPIToModel(sanitize.StorageProfile, prior.StorageProfile),
+	}
+}
+
+// shareAPIToModel converts the API share configuration into the Terraform
+// model. It is nil when the API returns an empty configuration and the prior
+// plan or state has no share block. The API returns an empty list of SSO
+// groups for a share without groups, which is null when the prior share block
+// has no sso_groups.
+func shareAPIToModel(ctx context.Context, share dbsTargetModel.ShareCfg, prior *targetShareModel) (*targetShareModel, error) {
+	ssoGroups := share.SsoGroups
+	if prior == nil {
+		if share.SsoGroups == nil {
+			return nil, nil
 		}
+	} else if len(ssoGroups) == 0 && prior.SSOGroups.IsNull() {
+		ssoGroups = nil
 	}
-	// Share
-	if targetApiResponse.Share.SsoGroups != nil {
-		l, diag := types.ListValueFrom(ctx, types.StringType, targetApiResponse.Share.SsoGroups)
+
+	shareModel := &targetShareModel{SSOGroups: types.SetNull(types.StringType)}
+	if ssoGroups != nil {
+		l, diag := types.SetValueFrom(ctx, types.StringType, uniqueStrings(ssoGroups))
 		if diag.HasError() {
-			return resourceModel, fmt.Errorf("Error mapping SSOGroups: %s", diag)
+			return shareModel, fmt.Errorf("Error mapping SSOGroups: %s", diag)
 		}
-		resourceModel.Share.SSOGroups = l
+		shareModel.SSOGroups = l
 	}
-	resourceModel.CreatedAt = types.StringValue(targetApiResponse.CreatedAt)
-	resourceModel.UpdatedAt = types.StringValue(targetApiResponse.UpdatedAt)
+	return shareModel, nil
+}
 
-	return resourceModel, nil
+// stringsFromSet reads a Terraform set of strings into a string slice.
+// A null or unknown set results in a nil slice.
+func stringsFromSet(ctx context.Context, s types.Set) ([]string, error) {
+	if s.IsNull() || s.IsUnknown() {
+		return nil, nil
+	}
+
+	elements := make([]types.String, 0, len(s.Elements()))
+	diags := s.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return nil, fmt.Errorf("%s", diags)
+	}
+
+	values := make([]string, len(elements))
+	for i, v := range elements {
+		values[i] = v.ValueString()
+	}
+	return values, nil
+}
+
+// uniqueStrings returns the values without duplicates, keeping the first
+// occurrence of each value. Sets must not contain duplicate elements.
+func uniqueStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
 }
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	dbsTargetModel "github.com/joescharf/dbsnapper/v2/models/target"
+	"github.com/joescharf/dbsnapper/v2/storage"
+)
+
+// The property tests generate targets from a seed, with every combination of
+// present and absent nested blocks, and of null, empty and set attributes.
+
+// randomOptionalString returns a null, empty or set string.
+func randomOptionalString(r *rand.Rand, value string) types.String {
+	switch r.Intn(3) {
+	case 0:
+		return types.StringNull()
+	case 1:
+		return types.StringValue("")
+	}
+	return types.StringValue(value)
+}
+
+// randomStrings returns up to three distinct strings with the prefix.
+func randomStrings(r *rand.Rand, prefix string) []attr.Value {
+	values := []attr.Value{}
+	for i := 0; i < r.Intn(4); i++ {
+		values = append(values, types.StringValue(fmt.Sprintf("%s_%d", prefix, i)))
+	}
+	return values
+}
+
+// randomStorageProfile returns an absent block, a block without id, or a block with an id.
+func randomStorageProfile(r *rand.Rand) *targetStorageProfileModel {
+	switch r.Intn(3) {
+	case 0:
+		return nil
+	case 1:
+		return &targetStorageProfileModel{ID: types.StringNull()}
+	}
+	return &targetStorageProfileModel{ID: types.StringValue(randomUUID(r).String())}
+}
+
+// randomUUID returns a UUID read from r, without changing the global source of
+// the uuid package that other tests use.
+func randomUUID(r *rand.Rand) uuid.UUID {
+	id, err := uuid.NewRandomFromReader(r)
+	if err != nil {
+		panic(fmt.Sprintf("unable to generate uuid: %s", err))
+	}
+	return id
+}
+
+// randomTarget returns the planned target generated from the seed.
+func randomTarget(seed int64) *TargetResourceModel {
+	r := rand.New(rand.NewSource(seed))
+
+	target := &TargetResourceModel{
+		ID:          types.StringUnknown(),
+		Name:        types.StringValue(fmt.Sprintf("target_%d", r.Intn(1000))),
+		Status:      types.StringUnknown(),
+		Messages:    types.StringUnknown(),
+		CreatedAt:   types.StringUnknown(),
+		UpdatedAt:   types.StringUnknown(),
+		IgnoreShare: types.BoolValue(r.Intn(4) == 0),
+		Snapshot: &targetSnapshotModel{
+			SrcURL:         types.StringValue("postgres://localhost/source"),
+			DstURL:         randomOptionalString(r, "postgres://localhost/destination"),
+			SrcBytes:       types.Int64Unknown(),
+			StorageProfile: randomStorageProfile(r),
+		},
+		WaitForStatus: types.StringNull(),
+		Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"update": types.StringType,
+		})},
+	}
+
+	if r.Intn(2) == 0 {
+		target.Sanitize = &targetSanitizeModel{
+			DstURL:         randomOptionalString(r, "postgres://localhost/sanitized"),
+			Query:          randomOptionalString(r, "UPDATE users SET email = NULL;"),
+			StorageProfile: randomStorageProfile(r),
+		}
+	}
+
+	if !target.IgnoreShare.ValueBool() && r.Intn(2) == 0 {
+		target.Share = &targetShareModel{SSOGroups: types.SetNull(types.StringType)}
+		if r.Intn(3) != 0 {
+			target.Share.SSOGroups = types.SetValueMust(types.StringType, randomStrings(r, "group"))
+		}
+	}
+
+	return target
+}
+
+// targetStateValue sets the target into a state of the target schema, which
+// fails for values that do not conform to the schema.
+func targetStateValue(t *testing.T, target *TargetResourceModel) (tfsdk.State, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	(&targetResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	diags := state.Set(ctx, target)
+	if diags.HasError() {
+		return state, fmt.Errorf("%s", diags)
+	}
+	return state, nil
+}
+
+// echoTarget returns the target the API responds with for the request.
+func echoTarget(request *dbsTargetModel.Target) *dbsTargetModel.Target {
+	response := *request
+	response.ID = uuid.New()
+	response.Status = "ready"
+	response.Snapshot.SrcBytes = 1024
+	response.CreatedAt = "2024-06-01T00:00:00Z"
+	response.UpdatedAt = "2024-06-01T00:00:00Z"
+	return &response
+}
+
+func TestTargetMappingRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	property := func(seed int64) bool {
+		request, err := ResourceModelToAPIRequest(ctx, randomTarget(seed))
+		if err != nil {
+			t.Logf("seed %d: unable to build the request: %s", seed, err)
+			return false
+		}
+		response := echoTarget(request)
+
+		got, err := APIResponseToResourceModel(ctx, response, randomTarget(seed))
+		if err != nil {
+			t.Logf("seed %d: unable to map the response: %s", seed, err)
+			return false
+		}
+
+		// The computed attributes are set from the response
+		want := randomTarget(seed)
+		want.ID = types.StringValue(response.ID.String())
+		want.Status = types.StringValue(response.Status)
+		want.Messages = types.StringValue(response.Messages)
+		want.Snapshot.SrcBytes = types.Int64Value(response.Snapshot.SrcBytes)
+		want.CreatedAt = types.StringValue(response.CreatedAt)
+		want.UpdatedAt = types.StringValue(response.UpdatedAt)
+
+		gotState, err := targetStateValue(t, got)
+		if err != nil {
+			t.Logf("seed %d: mapped target does not conform to the schema: %s", seed, err)
+			return false
+		}
+		wantState, err := targetStateValue(t, want)
+		if err != nil {
+			t.Logf("seed %d: planned target does not conform to the schema: %s", seed, err)
+			return false
+		}
+		if !gotState.Raw.Equal(wantState.Raw) {
+			t.Logf("seed %d: mapped target\n%s\nwant\n%s", seed, gotState.Raw, wantState.Raw)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTargetMappingArbitraryResponse(t *testing.T) {
+	ctx := context.Background()
+
+	// The API may omit any nested configuration, including the pointers
+	property := func(seed int64, omit uint8) bool {
+		request, err := ResourceModelToAPIRequest(ctx, randomTarget(seed))
+		if err != nil {
+			t.Logf("seed %d: unable to build the request: %s", seed, err)
+			return false
+		}
+		response := echoTarget(request)
+		if omit&1 != 0 {
+			response.Snapshot = dbsTargetModel.SnapshotCfg{}
+		}
+		if omit&2 != 0 {
+			response.Sanitize = dbsTargetModel.SanitizeCfg{}
+		}
+		if omit&4 != 0 {
+			response.Sanitize.StorageProfile = &storage.StorageProfile{}
+		}
+		if omit&8 != 0 {
+			response.Share = dbsTargetModel.ShareCfg{}
+		}
+
+		// Against an imported target without prior blocks
+		got, err := APIResponseToResourceModel(ctx, response, &TargetResourceModel{})
+		if err != nil || got.Snapshot == nil {
+			t.Logf("seed %d, omit %04b: unable to map the response to an imported target: %v", seed, omit, err)
+			return false
+		}
+
+		// Against the plan, the mapped target must still conform to the schema
+		got, err = APIResponseToResourceModel(ctx, response, randomTarget(seed))
+		if err != nil {
+			t.Logf("seed %d, omit %04b: unable to map the response: %s", seed, omit, err)
+			return false
+		}
+		if _, err := targetStateValue(t, got); err != nil {
+			t.Logf("seed %d, omit %04b: mapped target does not conform to the schema: %s", seed, omit, err)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTargetMappingNilResponse(t *testing.T) {
+	if _, err := APIResponseToResourceModel(context.Background(), nil, &TargetResourceModel{}); err == nil {
+		t.Error("expected an error for a nil response")
+	}
+}
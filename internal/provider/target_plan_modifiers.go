@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// targetLocalAttributes are the attributes of dbsnapper_target that are not
+// sent to the API: the computed attributes and the attributes that only
+// control the provider. Changing them alone does not update the target.
+var targetLocalAttributes = []*tftypes.AttributePath{
+	tftypes.NewAttributePath().WithAttributeName("id"),
+	tftypes.NewAttributePath().WithAttributeName("created_at"),
+	tftypes.NewAttributePath().WithAttributeName("updated_at"),
+	tftypes.NewAttributePath().WithAttributeName("status"),
+	tftypes.NewAttributePath().WithAttributeName("messages"),
+	tftypes.NewAttributePath().WithAttributeName("wait_for_status"),
+	tftypes.NewAttributePath().WithAttributeName("ignore_share"),
+	tftypes.NewAttributePath().WithAttributeName("timeouts"),
+	tftypes.NewAttributePath().WithAttributeName("snapshot").WithAttributeName("src_bytes"),
+}
+
+// targetAPIAttributesChanged reports whether the plan changes any of the
+// attributes of the target that are sent to the API, and so whether applying
+// the plan updates the target. A plan without prior state creates the target.
+func targetAPIAttributesChanged(plan tfsdk.Plan, state tfsdk.State) (bool, error) {
+	if state.Raw.IsNull() {
+		return true, nil
+	}
+
+	planValue, err := withoutTargetLocalAttributes(plan.Raw)
+	if err != nil {
+		return false, err
+	}
+	stateValue, err := withoutTargetLocalAttributes(state.Raw)
+	if err != nil {
+		return false, err
+	}
+
+	return !planValue.Equal(stateValue), nil
+}
+
+// withoutTargetLocalAttributes returns the value with the targetLocalAttributes set to null.
+func withoutTargetLocalAttributes(value tftypes.Value) (tftypes.Value, error) {
+	return tftypes.Transform(value, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		for _, local := range targetLocalAttributes {
+			if p.Equal(local) {
+				return tftypes.NewValue(v.Type(), nil), nil
+			}
+		}
+		return v, nil
+	})
+}
+
+// useStateUnlessTargetChanged returns a plan modifier that copies the prior
+// state value of a computed attribute into the plan, unless the plan updates
+// the target. Unlike UseStateForUnknown, the attribute is still planned as
+// unknown when the API may change it, e.g. updated_at after a changed name.
+func useStateUnlessTargetChanged() useStateUnlessTargetChangedModifier {
+	return useStateUnlessTargetChangedModifier{}
+}
+
+// useStateUnlessTargetChangedModifier implements the plan modifier.
+type useStateUnlessTargetChangedModifier struct{}
+
+var (
+	_ planmodifier.String = useStateUnlessTargetChangedModifier{}
+	_ planmodifier.Int64  = useStateUnlessTargetChangedModifier{}
+)
+
+func (m useStateUnlessTargetChangedModifier) Description(_ context.Context) string {
+	return "Once set, the value of this attribute in state will not change unless an attribute of the target that is sent to the API changes."
+}
+
+func (m useStateUnlessTargetChangedModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m useStateUnlessTargetChangedModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to copy on create, and nothing to do for known planned values
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	changed, err := targetAPIAttributesChanged(req.Plan, req.State)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Plan Modifier Error", fmt.Sprintf("Unable to compare the plan to the state: %s", err))
+		return
+	}
+	if !changed {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+func (m useStateUnlessTargetChangedModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	// Nothing to copy on create, and nothing to do for known planned values
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	changed, err := targetAPIAttributesChanged(req.Plan, req.State)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Plan Modifier Error", fmt.Sprintf("Unable to compare the plan to the state: %s", err))
+		return
+	}
+	if !changed {
+		resp.PlanValue = req.StateValue
+	}
+}
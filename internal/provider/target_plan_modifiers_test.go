@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testTargetPriorState = `{
+	"id": "6b1d3c1e-5e2a-4c8b-9f3d-2a4e6c8b0d1f",
+	"name": "tf_test",
+	"created_at": "2024-06-01T00:00:00Z",
+	"updated_at": "2024-06-02T00:00:00Z",
+	"status": "ready",
+	"messages": "",
+	"ignore_share": false,
+	"snapshot": {"src_url": "postgres://localhost/tf_test", "src_bytes": 1024}
+}`
+
+// planTestTarget plans the change of the prior state to a configuration with
+// the given attribute values, and returns the planned state.
+func planTestTarget(t *testing.T, prior tftypes.Value, changes map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+	ctx := context.Background()
+
+	server, err := testAccProtoV6ProviderFactories["dbsnapper"]()
+	if err != nil {
+		t.Fatalf("unable to create provider server: %s", err)
+	}
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	valueType := schemaResp.ResourceSchemas["dbsnapper_target"].ValueType()
+
+	// Terraform proposes the prior computed values, the configuration leaves them out
+	computed := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("id"),
+		tftypes.NewAttributePath().WithAttributeName("created_at"),
+		tftypes.NewAttributePath().WithAttributeName("updated_at"),
+		tftypes.NewAttributePath().WithAttributeName("status"),
+		tftypes.NewAttributePath().WithAttributeName("messages"),
+		tftypes.NewAttributePath().WithAttributeName("ignore_share"),
+		tftypes.NewAttributePath().WithAttributeName("snapshot").WithAttributeName("src_bytes"),
+	}
+
+	transform := func(nullComputed bool) tftypes.Value {
+		v, err := tftypes.Transform(prior, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+			for name, changed := range changes {
+				if p.Equal(tftypes.NewAttributePath().WithAttributeName(name)) {
+					return changed, nil
+				}
+			}
+			if nullComputed {
+				for _, c := range computed {
+					if p.Equal(c) {
+						return tftypes.NewValue(v.Type(), nil), nil
+					}
+				}
+			}
+			return v, nil
+		})
+		if err != nil {
+			t.Fatalf("unable to build the plan request: %s", err)
+		}
+		return v
+	}
+
+	dynamicValue := func(v tftypes.Value) *tfprotov6.DynamicValue {
+		dv, err := tfprotov6.NewDynamicValue(valueType, v)
+		if err != nil {
+			t.Fatalf("unable to encode value: %s", err)
+		}
+		return &dv
+	}
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "dbsnapper_target",
+		PriorState:       dynamicValue(prior),
+		ProposedNewState: dynamicValue(transform(false)),
+		Config:           dynamicValue(transform(true)),
+	})
+	if err != nil {
+		t.Fatalf("unable to plan: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	planned, err := resp.PlannedState.Unmarshal(valueType)
+	if err != nil {
+		t.Fatalf("unable to decode planned state: %s", err)
+	}
+	return planned
+}
+
+func TestTargetComputedAttributesPlan(t *testing.T) {
+	prior, diags := upgradeTestState(t, "dbsnapper_target", targetSchemaVersion, testTargetPriorState)
+	for _, d := range diags {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	testCases := map[string]struct {
+		changes     map[string]tftypes.Value
+		wantUnknown bool
+	}{
+		"no change": {},
+		"provider setting changed": {
+			changes: map[string]tftypes.Value{"wait_for_status": tftypes.NewValue(tftypes.String, "ready")},
+		},
+		"ignore_share changed": {
+			changes: map[string]tftypes.Value{"ignore_share": tftypes.NewValue(tftypes.Bool, true)},
+		},
+		"name changed": {
+			changes:     map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "tf_test_renamed")},
+			wantUnknown: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planTestTarget(t, prior, tc.changes)
+
+			// created_at never changes
+			if got := stateString(t, planned, "created_at"); got != "2024-06-01T00:00:00Z" {
+				t.Errorf("created_at = %q, want the prior value", got)
+			}
+
+			for _, path := range [][]string{{"updated_at"}, {"status"}, {"messages"}, {"snapshot", "src_bytes"}} {
+				v := stateValue(t, planned, path...)
+				if v.IsKnown() == tc.wantUnknown {
+					t.Errorf("%v known = %t, want %t", path, v.IsKnown(), !tc.wantUnknown)
+				}
+			}
+			if !tc.wantUnknown {
+				if got := stateString(t, planned, "updated_at"); got != "2024-06-02T00:00:00Z" {
+					t.Errorf("updated_at = %q, want the prior value", got)
+				}
+			}
+		})
+	}
+}
@@ -6,22 +6,38 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-dbsnapper/internal/client"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	dbsTargetModel "github.com/joescharf/dbsnapper/v2/models/target"
+)
+
+const (
+	targetStatusError = "error"
+
+	defaultTargetWaitTimeout = 20 * time.Minute
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &targetResource{}
-	_ resource.ResourceWithConfigure   = &targetResource{}
-	_ resource.ResourceWithImportState = &targetResource{}
+	_ resource.Resource                   = &targetResource{}
+	_ resource.ResourceWithConfigure      = &targetResource{}
+	_ resource.ResourceWithImportState    = &targetResource{}
+	_ resource.ResourceWithValidateConfig = &targetResource{}
+	_ resource.ResourceWithUpgradeState   = &targetResource{}
 )
 
 func NewTargetResource() resource.Resource {
@@ -30,7 +46,7 @@ func NewTargetResource() resource.Resource {
 
 // targetResource defines the resource implementation.
 type targetResource struct {
-	client *client.DBSnapper
+	client client.API
 }
 
 type TargetResourceModel struct {
@@ -43,6 +59,10 @@ type TargetResourceModel struct {
 	Share     *targetShareModel    `tfsdk:"share"`
 	CreatedAt types.String         `tfsdk:"created_at"`
 	UpdatedAt types.String         `tfsdk:"updated_at"`
+
+	IgnoreShare   types.Bool     `tfsdk:"ignore_share"`
+	WaitForStatus types.String   `tfsdk:"wait_for_status"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 // targetSnapshotModel maps snapshot data.
@@ -62,7 +82,7 @@ type targetSanitizeModel struct {
 
 // targetShareModel maps share data.
 type targetShareModel struct {
-	SSOGroups types.List `tfsdk:"sso_groups"`
+	SSOGroups types.Set `tfsdk:"sso_groups"`
 }
 
 type targetStorageProfileModel struct {
@@ -76,6 +96,7 @@ func (r *targetResource) Metadata(ctx context.Context, req resource.MetadataRequ
 func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Target resource",
+		Version:             targetSchemaVersion,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -88,10 +109,16 @@ func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"created_at": schema.StringAttribute{
 				Description: "The time the target was created",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"updated_at": schema.StringAttribute{
 				Description: "The time the target was last updated",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					useStateUnlessTargetChanged(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "The name of the target",
@@ -100,11 +127,28 @@ func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"status": schema.StringAttribute{
 				Description: "The status of the target - determined by agent",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					useStateUnlessTargetChanged(),
+				},
 			},
 			"messages": schema.StringAttribute{
 				Description: "The error messages from the target - determined by agent",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					useStateUnlessTargetChanged(),
+				},
 			},
+			"wait_for_status": schema.StringAttribute{
+				Description: "Wait after create and update until the agent reports this status (e.g. ready) - fails when the target ends in the error status",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
 
 			"snapshot": schema.SingleNestedAttribute{
 				Description: "The snapshot configuration",
@@ -121,6 +165,9 @@ func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					"src_bytes": schema.Int64Attribute{
 						Description: "The size of the source database in bytes",
 						Computed:    true,
+						PlanModifiers: []planmodifier.Int64{
+							useStateUnlessTargetChanged(),
+						},
 					},
 					"storage_profile": schema.SingleNestedAttribute{
 						Description: "Storage provider configuration for Snapshots",
@@ -160,11 +207,17 @@ func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 
+			"ignore_share": schema.BoolAttribute{
+				Description: "Leave the share configuration of the target untouched, for when it is managed with dbsnapper_target_share resources - defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
 			"share": schema.SingleNestedAttribute{
-				Description: "The share configuration",
+				Description: "The share configuration - must not be set when ignore_share is true",
 				Optional:    true,
 				Attributes: map[string]schema.Attribute{
-					"sso_groups": schema.ListAttribute{
+					"sso_groups": schema.SetAttribute{
 						Description: "The SSO groups that have access to the target snapshot",
 						Optional:    true,
 						ElementType: types.StringType,
@@ -175,18 +228,37 @@ func (r *targetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	}
 }
 
+// ValidateConfig rejects a share configuration that would be ignored.
+func (r *targetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config TargetResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.IgnoreShare.ValueBool() && config.Share != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("share"),
+			"Conflicting Share Configuration",
+			"The share attribute cannot be set when ignore_share is true. Manage the shares with dbsnapper_target_share resources instead.",
+		)
+	}
+}
+
 func (r *targetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.DBSnapper)
+	client, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *DBSnapper, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -224,7 +296,7 @@ func (r *targetResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Call API to create target
-	targetResponse, err := r.client.API.CreateTarget(targetApiRequest)
+	targetResponse, err := r.client.CreateTarget(ctx, targetApiRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create target, got error: %s", err))
 		return
@@ -241,6 +313,23 @@ func (r *targetResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+	if resp.Diagnostics.HasError() || plan.WaitForStatus.IsNull() {
+		return
+	}
+
+	// Wait for the agent to report the requested status
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTargetWaitTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan, diags = r.waitForStatus(ctx, plan, createTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	// Save the last known status into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 ////////////////////////////// READ //////////////////////////////
@@ -263,7 +352,7 @@ func (r *targetResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	// Call API to get refreshed target data
-	targetResponse, err := r.client.API.GetTarget(state.ID.ValueString())
+	targetResponse, err := r.client.GetTarget(ctx, state.ID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read target, got error: %s\n Could not read Target ID: %s\n", err, state.ID.ValueString()))
@@ -305,6 +394,19 @@ func (r *targetResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// Only provider settings like wait_for_status or timeouts changed, the plan
+	// carries the computed values of the state, see useStateUnlessTargetChanged
+	changed, err := targetAPIAttributesChanged(req.Plan, req.State)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading Terraform plan", fmt.Sprintf("Unable to compare the plan to the state, got error: %s", err))
+		return
+	}
+	if !changed {
+		tflog.Info(ctx, "DBSnapper Provider: Update Target Resource without API changes")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	// Generate API Request Body
 	targetApiRequest, err := ResourceModelToAPIRequest(ctx, plan)
 	if err != nil {
@@ -312,8 +414,21 @@ func (r *targetResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// Keep the externally managed share configuration
+	if plan.IgnoreShare.ValueBool() {
+		unlock := lockTargetShare(plan.ID.ValueString())
+		defer unlock()
+
+		currentTarget, err := r.client.GetTarget(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read target %s, got error: %s", plan.ID.ValueString(), err))
+			return
+		}
+		targetApiRequest.Share = currentTarget.Share
+	}
+
 	// Update target via API
-	targetResponse, err := r.client.API.UpdateTarget(plan.ID.ValueString(), targetApiRequest)
+	targetResponse, err := r.client.UpdateTarget(ctx, plan.ID.ValueString(), targetApiRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Errorf("Unable to update target id: %s, got error: %w", targetResponse.ID.String(), err).Error())
 		return
@@ -330,6 +445,71 @@ func (r *targetResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.WaitForStatus.IsNull() {
+		return
+	}
+
+	// Wait for the agent to report the requested status
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultTargetWaitTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan, diags = r.waitForStatus(ctx, plan, updateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	// Save the last known status into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+////////////////////////////// WAIT //////////////////////////////
+
+// waitForStatus polls the target until the agent reports the wait_for_status
+// status. The target's messages are returned as error diagnostics when it
+// ends in the error status instead.
+func (r *targetResource) waitForStatus(ctx context.Context, plan *TargetResourceModel, timeout time.Duration) (*TargetResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	wantStatus := plan.WaitForStatus.ValueString()
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := waitFor(waitCtx, func(ctx context.Context) (bool, error) {
+		targetResponse, err := r.client.GetTarget(ctx, plan.ID.ValueString())
+		if err != nil {
+			return false, err
+		}
+
+		plan.Status = types.StringValue(targetResponse.Status)
+		plan.Messages = types.StringValue(targetResponse.Messages)
+		tflog.Debug(ctx, "DBSnapper Provider: Waiting for target status", map[string]interface{}{"id": plan.ID.ValueString(), "status": targetResponse.Status, "want": wantStatus})
+
+		return targetResponse.Status == wantStatus || targetResponse.Status == targetStatusError, nil
+	})
+	if err != nil {
+		diags.AddError(
+			"Target Status Error",
+			fmt.Sprintf("Target %s did not reach status %q, last status %q: %s", plan.ID.ValueString(), wantStatus, plan.Status.ValueString(), err),
+		)
+		return plan, diags
+	}
+
+	if plan.Status.ValueString() != wantStatus {
+		for _, message := range strings.Split(plan.Messages.ValueString(), "\n") {
+			if message = strings.TrimSpace(message); message != "" {
+				diags.AddAttributeError(path.Root("messages"), "Target Error", message)
+			}
+		}
+		diags.AddError(
+			"Target Status Error",
+			fmt.Sprintf("Target %s ended in status %q while waiting for status %q", plan.ID.ValueString(), plan.Status.ValueString(), wantStatus),
+		)
+	}
+
+	return plan, diags
 }
 
 ////////////////////////////// DELETE //////////////////////////////
@@ -346,7 +526,7 @@ func (r *targetResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	// Delete target via API
-	err := r.client.API.DeleteTarget(data.ID.ValueString())
+	err := r.client.DeleteTarget(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete target, got error: %s", err))
 		return
@@ -355,7 +535,29 @@ func (r *targetResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 ////////////////////////////// IMPORT STATE //////////////////////////////
 
-// ImportState imports the target resource state.
+// ImportState imports the target resource state, either by ID or by name in
+// the form name:<name>.
 func (r *targetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id := req.ID
+
+	if name, ok := importName(req.ID); ok {
+		// Call API to resolve the name
+		targets, err := r.client.ListTargets(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list targets, got error: %s", err))
+			return
+		}
+
+		id, err = resolveImportName("target", name, targets,
+			func(t dbsTargetModel.Target) string { return t.Name },
+			func(t dbsTargetModel.Target) string { return t.ID.String() },
+		)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Import Target", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ignore_share"), false)...)
 }
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"terraform-provider-dbsnapper/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// targetShareIDSeparator separates the target ID from the SSO group in the
+// target share ID. Target IDs are UUIDs, so the first separator always ends
+// the target ID even when the SSO group contains it.
+const targetShareIDSeparator = "/"
+
+// targetShareLocks serializes the read-modify-write updates of a target's
+// share configuration, so that shares of the same target created in parallel
+// do not overwrite each other.
+var targetShareLocks sync.Map
+
+// lockTargetShare locks the share configuration of the target and returns the unlock function.
+func lockTargetShare(targetID string) func() {
+	mu, _ := targetShareLocks.LoadOrStore(targetID, new(sync.Mutex))
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &targetShareResource{}
+	_ resource.ResourceWithConfigure   = &targetShareResource{}
+	_ resource.ResourceWithImportState = &targetShareResource{}
+)
+
+func NewTargetShareResource() resource.Resource {
+	return &targetShareResource{}
+}
+
+// targetShareResource defines the resource implementation.
+type targetShareResource struct {
+	client client.API
+}
+
+type TargetShareResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	TargetID types.String `tfsdk:"target_id"`
+	SSOGroup types.String `tfsdk:"sso_group"`
+}
+
+func (r *targetShareResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_target_share"
+}
+
+func (r *targetShareResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Target Share resource - shares the snapshots of a target with an SSO group. " +
+			"Set `ignore_share = true` on the `dbsnapper_target` when its shares are managed with this resource.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The identifier of the share, in the form <target_id>/<sso_group>",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"target_id": schema.StringAttribute{
+				Description: "The unique identifier of the target to share - changing this creates a new share",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sso_group": schema.StringAttribute{
+				Description: "The SSO group that has access to the target snapshots - changing this creates a new share",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *targetShareResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(client.API)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+////////////////////////////// CREATE //////////////////////////////
+
+// Create adds the SSO group to the share configuration of the target.
+func (r *targetShareResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TargetShareResourceModel
+
+	// Read Terraform PLAN into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targetID := plan.TargetID.ValueString()
+	ssoGroup := plan.SSOGroup.ValueString()
+
+	unlock := lockTargetShare(targetID)
+	defer unlock()
+
+	// Call API to get the current share configuration
+	target, err := r.client.GetTarget(ctx, targetID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read target %s, got error: %s", targetID, err))
+		return
+	}
+
+	if !slices.Contains(target.Share.SsoGroups, ssoGroup) {
+		target.Share.SsoGroups = append(target.Share.SsoGroups, ssoGroup)
+
+		// Call API to update the share configuration
+		_, err = r.client.UpdateTarget(ctx, targetID, target)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to share target %s with SSO group %s, got error: %s", targetID, ssoGroup, err))
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(targetID + targetShareIDSeparator + ssoGroup)
+
+	tflog.Info(ctx, "DBSnapper Provider: Create Target Share Resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+////////////////////////////// READ //////////////////////////////
+
+// Read removes the share from state when the SSO group no longer has access to the target.
+func (r *targetShareResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TargetShareResourceModel
+
+	// Read Terraform prior STATE data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Call API to get the current share configuration
+	target, err := r.client.GetTarget(ctx, state.TargetID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read target %s, got error: %s", state.TargetID.ValueString(), err))
+		return
+	}
+
+	if !slices.Contains(target.Share.SsoGroups, state.SSOGroup.ValueString()) {
+		tflog.Info(ctx, "DBSnapper Provider: Target Share removed outside of Terraform", map[string]interface{}{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Info(ctx, "DBSnapper Provider: Read Target Share Resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+////////////////////////////// UPDATE //////////////////////////////
+
+// Update is never called with a changed share, as every attribute requires a
+// new share.
+func (r *targetShareResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan TargetShareResourceModel
+
+	// Read Terraform PLAN data into the model.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+////////////////////////////// DELETE //////////////////////////////
+
+// Delete removes the SSO group from the share configuration of the target.
+func (r *targetShareResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data TargetShareResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targetID := data.TargetID.ValueString()
+	ssoGroup := data.SSOGroup.ValueString()
+
+	unlock := lockTargetShare(targetID)
+	defer unlock()
+
+	// Call API to get the current share configuration
+	target, err := r.client.GetTarget(ctx, targetID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read target %s, got error: %s", targetID, err))
+		return
+	}
+
+	if !slices.Contains(target.Share.SsoGroups, ssoGroup) {
+		return
+	}
+	target.Share.SsoGroups = slices.DeleteFunc(target.Share.SsoGroups, func(group string) bool {
+		return group == ssoGroup
+	})
+
+	// Call API to update the share configuration
+	_, err = r.client.UpdateTarget(ctx, targetID, target)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to unshare target %s from SSO group %s, got error: %s", targetID, ssoGroup, err))
+		return
+	}
+}
+
+////////////////////////////// IMPORT STATE //////////////////////////////
+
+// ImportState imports the target share from an ID in the form <target_id>/<sso_group>.
+func (r *targetShareResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	targetID, ssoGroup, ok := strings.Cut(req.ID, targetShareIDSeparator)
+	if !ok || targetID == "" || ssoGroup == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: <target_id>%s<sso_group>. Got: %q", targetShareIDSeparator, req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_id"), targetID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("sso_group"), ssoGroup)...)
+}
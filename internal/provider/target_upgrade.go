@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// targetSchemaVersion is the current version of the target resource schema.
+//
+//   - Version 0 stored share.sso_groups as a list, which may contain the same
+//     group twice.
+//   - Version 1 stores share.sso_groups as a set.
+const targetSchemaVersion = 1
+
+// UpgradeState upgrades the target state written by earlier versions of the schema.
+func (r *targetResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: rawStateUpgrader("target", 0, upgradeTargetStateV0),
+	}
+}
+
+// upgradeTargetStateV0 converts a version 0 target state into version 1.
+func upgradeTargetStateV0(state map[string]interface{}) error {
+	// share.sso_groups changed from a list to a set, drop duplicate groups
+	if share, ok := state["share"].(map[string]interface{}); ok {
+		if groups, ok := share["sso_groups"].([]interface{}); ok {
+			unique := make([]interface{}, 0, len(groups))
+			seen := make(map[interface{}]bool, len(groups))
+			for _, group := range groups {
+				if !seen[group] {
+					seen[group] = true
+					unique = append(unique, group)
+				}
+			}
+			share["sso_groups"] = unique
+		}
+	}
+
+	// Attributes with a default are added with their default value, so that
+	// the first plan after the upgrade does not show a change
+	if _, ok := state["ignore_share"]; !ok {
+		state["ignore_share"] = false
+	}
+
+	return nil
+}
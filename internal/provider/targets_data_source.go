@@ -24,12 +24,26 @@ func NewTargetsDataSource() datasource.DataSource {
 
 // TargetsDataSource is the data source implementation.
 type TargetsDataSource struct {
-	client *client.DBSnapper
+	client client.API
 }
 
 // TargetsDataSourceModel maps the data source schema data.
 type TargetsDataSourceModel struct {
-	Targets []TargetResourceModel `tfsdk:"targets"`
+	Targets []targetDataSourceModel `tfsdk:"targets"`
+}
+
+// targetDataSourceModel maps a single target. It mirrors TargetResourceModel
+// without the resource-only settings such as wait_for_status and timeouts.
+type targetDataSourceModel struct {
+	ID        types.String         `tfsdk:"id"`
+	Name      types.String         `tfsdk:"name"`
+	Status    types.String         `tfsdk:"status"`
+	Messages  types.String         `tfsdk:"messages"`
+	Snapshot  *targetSnapshotModel `tfsdk:"snapshot"`
+	Sanitize  *targetSanitizeModel `tfsdk:"sanitize"`
+	Share     *targetShareModel    `tfsdk:"share"`
+	CreatedAt types.String         `tfsdk:"created_at"`
+	UpdatedAt types.String         `tfsdk:"updated_at"`
 }
 
 // Metadata returns the data source type name.
@@ -130,7 +144,7 @@ func (d *TargetsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 							Description: "The share configuration",
 							Optional:    true,
 							Attributes: map[string]schema.Attribute{
-								"sso_groups": schema.ListAttribute{
+								"sso_groups": schema.SetAttribute{
 									Description: "The SSO groups that have access to the target snapshot",
 									Optional:    true,
 									ElementType: types.StringType,
@@ -148,12 +162,12 @@ func (d *TargetsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 func (d *TargetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state TargetsDataSourceModel
 
-	targets := d.client.API.GetTargetsTF()
+	targets, err := d.client.ListTargets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list targets, got error: %s", err))
+		return
+	}
 
-	// if err != nil {
-	// 	resp.Diagnostics.AddError("Error fetching targets", err.Error())
-	// 	return
-	// }
 	for _, target := range targets {
 		idstr := target.ID.String()
 		snapStorageProfile := ""
@@ -164,7 +178,7 @@ func (d *TargetsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		if target.Sanitize.StorageProfile != nil {
 			sanStorageProfile = target.Sanitize.StorageProfile.ID.String()
 		}
-		targetState := TargetResourceModel{
+		targetState := targetDataSourceModel{
 
 			ID:       types.StringValue(idstr),
 			Name:     types.StringValue(target.Name),
@@ -188,7 +202,7 @@ func (d *TargetsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		}
 		// Share
 		if target.Share.SsoGroups != nil {
-			l, diag := types.ListValueFrom(ctx, types.StringType, target.Share.SsoGroups)
+			l, diag := types.SetValueFrom(ctx, types.StringType, uniqueStrings(target.Share.SsoGroups))
 			if diag.HasError() {
 				resp.Diagnostics.AddError("Error reading SSOGroups", "")
 				return
@@ -219,11 +233,11 @@ func (d *TargetsDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.DBSnapper)
+	client, ok := req.ProviderData.(client.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *DBSnapper, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -6,6 +6,9 @@ package provider
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/joescharf/dbsnapper/v2/models/target"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -20,7 +23,7 @@ func TestAccTargetsDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.dbsnapper_targets.test", "targets.#", "2"),
 					resource.TestCheckResourceAttr("data.dbsnapper_targets.test", "targets.0.name", "tf_target_1"),
 					resource.TestCheckResourceAttr("data.dbsnapper_targets.test", "targets.0.share.sso_groups.#", "2"),
-					resource.TestCheckResourceAttr("data.dbsnapper_targets.test", "targets.0.share.sso_groups.0", "target1"),
+					resource.TestCheckTypeSetElemAttr("data.dbsnapper_targets.test", "targets.0.share.sso_groups.*", "target1"),
 					resource.TestCheckResourceAttr("data.dbsnapper_targets.test", "targets.1.name", "tf_target_2"),
 				),
 			},
@@ -32,3 +35,28 @@ const testAccTargetsDataSourceConfig = `
 data "dbsnapper_targets" "test" {
 }
 `
+
+func TestTargetsDataSourceRead(t *testing.T) {
+	api := newFakeAPI(t)
+	h := newProviderHarness(t, api)
+
+	api.addTarget(target.Target{Name: "tf_test_a", Snapshot: target.SnapshotCfg{SrcURL: "postgres://localhost/a"}})
+	api.addTarget(target.Target{Name: "tf_test_b", Share: target.ShareCfg{SsoGroups: []string{"group1"}}})
+
+	state, diags := h.readDataSource("dbsnapper_targets", `{}`)
+	requireNoErrors(t, diags)
+
+	var targets []tftypes.Value
+	if err := stateValue(t, state, "targets").As(&targets); err != nil {
+		t.Fatalf("targets is not a list: %s", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("read %d targets, want 2", len(targets))
+	}
+	for i, name := range []string{"tf_test_a", "tf_test_b"} {
+		var got string
+		if err := stateValue(t, targets[i], "name").As(&got); err != nil || got != name {
+			t.Errorf("targets[%d].name = %q, want %q", i, got, name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// pollInterval is the time between two status checks while waiting on the API.
+var pollInterval = 10 * time.Second
+
+// waitFor calls check every pollInterval until it reports done, returns an
+// error, or the context expires. check is passed the context, so that the API
+// calls it makes are bounded by the same deadline.
+func waitFor(ctx context.Context, check func(ctx context.Context) (done bool, err error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 	"terraform-provider-dbsnapper/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -32,6 +33,24 @@ var (
 )
 
 func main() {
+	// The export and convert subcommands write Terraform configuration
+	// instead of serving the provider.
+	if len(os.Args) > 1 {
+		var run func(args []string) error
+		switch os.Args[1] {
+		case "export":
+			run = runExport
+		case "convert":
+			run = runConvert
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err.Error())
+			}
+			return
+		}
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")